@@ -2,6 +2,7 @@ package lexical
 
 import (
 	"context"
+	"fmt"
 
 	"github.com/tminor/jsonnet-language-server/pkg/analysis/lexical/token"
 	"github.com/tminor/jsonnet-language-server/pkg/config"
@@ -19,15 +20,31 @@ type DocumentProcessor interface {
 	Process(ctx context.Context, td config.TextDocument, conn RPCConn) error
 }
 
+const (
+	// DiagnosticUnknownField is the code for diagnostics about indexes of
+	// fields which do not exist.
+	DiagnosticUnknownField = "unknown-field"
+)
+
+// PerformDiagnosticsConfig is configuration for PerformDiagnostics.
+type PerformDiagnosticsConfig interface {
+	JsonnetLibPaths() []string
+	NodeCache() *token.NodeCache
+}
+
 // PerformDiagnostics performs diagnostics on a text document and sends results
 // to the client.
-type PerformDiagnostics struct{}
+type PerformDiagnostics struct {
+	config PerformDiagnosticsConfig
+}
 
 var _ DocumentProcessor = (*PerformDiagnostics)(nil)
 
 // NewPerformDiagnostics creates an instance of PerformDiagnostics.
-func NewPerformDiagnostics() *PerformDiagnostics {
-	return &PerformDiagnostics{}
+func NewPerformDiagnostics(c PerformDiagnosticsConfig) *PerformDiagnostics {
+	return &PerformDiagnostics{
+		config: c,
+	}
 }
 
 // Process runs the diagnositics.
@@ -79,6 +96,9 @@ func (p *PerformDiagnostics) Process(ctx context.Context, td config.TextDocument
 
 	<-done
 
+	diagnostics = append(diagnostics, p.importDiagnostics(filename, td.String())...)
+	diagnostics = append(diagnostics, p.unknownFieldDiagnostics(filename, td.String())...)
+
 	if conn != nil {
 		span.LogFields(
 			log.String("event", "sending diagnostics"),
@@ -103,6 +123,59 @@ func (p *PerformDiagnostics) Process(ctx context.Context, td config.TextDocument
 	return nil
 }
 
+// importDiagnostics reports imports which can't be resolved.
+func (p *PerformDiagnostics) importDiagnostics(filename, source string) []lsp.Diagnostic {
+	var libPaths []string
+	if p.config != nil {
+		libPaths = p.config.JsonnetLibPaths()
+	}
+
+	links, err := token.ImportLinks(filename, source, libPaths)
+	if err != nil {
+		return nil
+	}
+
+	var diagnostics []lsp.Diagnostic
+	for _, link := range links {
+		if link.IsResolved() {
+			continue
+		}
+
+		diagnostics = append(diagnostics, lsp.Diagnostic{
+			Range:    link.Range.ToLSP(),
+			Message:  fmt.Sprintf("import %q not found", link.Name),
+			Severity: lsp.Error,
+		})
+	}
+
+	return diagnostics
+}
+
+// unknownFieldDiagnostics warns about indexes of fields which do not exist
+// in objects whose fields are known.
+func (p *PerformDiagnostics) unknownFieldDiagnostics(filename, source string) []lsp.Diagnostic {
+	if p.config == nil {
+		return nil
+	}
+
+	unknown, err := token.UnknownFields(filename, source, p.config.NodeCache(), p.config.JsonnetLibPaths())
+	if err != nil {
+		return nil
+	}
+
+	var diagnostics []lsp.Diagnostic
+	for i := range unknown {
+		diagnostics = append(diagnostics, lsp.Diagnostic{
+			Range:    unknown[i].Range.ToLSP(),
+			Message:  unknown[i].Message(),
+			Severity: lsp.Warning,
+			Code:     DiagnosticUnknownField,
+		})
+	}
+
+	return diagnostics
+}
+
 func convertToNode(filename, snippet string, diagCh chan<- token.ParseDiagnostic) (ast.Node, error) {
 	node, err := token.Parse(filename, snippet, diagCh)
 	if err != nil {
@@ -1 +1,61 @@
 package lexical
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/tminor/jsonnet-language-server/pkg/analysis/lexical/token"
+	"github.com/tminor/jsonnet-language-server/pkg/lsp"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestPerformDiagnostics_importDiagnostics(t *testing.T) {
+	dir, err := ioutil.TempDir("", "")
+	require.NoError(t, err)
+	defer os.RemoveAll(dir)
+
+	err = ioutil.WriteFile(filepath.Join(dir, "lib.libsonnet"), []byte("{}"), 0600)
+	require.NoError(t, err)
+
+	p := NewPerformDiagnostics(&fakePerformDiagnosticsConfig{})
+
+	source := "local a = import 'lib.libsonnet';\nlocal b = importstr 'missing.txt';\n[a, b]"
+
+	expected := []lsp.Diagnostic{
+		{
+			Range: lsp.Range{
+				Start: lsp.Position{Line: 1, Character: 21},
+				End:   lsp.Position{Line: 1, Character: 32},
+			},
+			Message:  `import "missing.txt" not found`,
+			Severity: lsp.Error,
+		},
+	}
+
+	got := p.importDiagnostics(filepath.Join(dir, "file.jsonnet"), source)
+	assert.Equal(t, expected, got)
+}
+
+func TestPerformDiagnostics_unknownFieldDiagnostics(t *testing.T) {
+	p := NewPerformDiagnostics(&fakePerformDiagnosticsConfig{nodeCache: token.NewNodeCache()})
+
+	source := "local o = { field: 1 };\no.feild"
+
+	expected := []lsp.Diagnostic{
+		{
+			Range: lsp.Range{
+				Start: lsp.Position{Line: 1, Character: 2},
+				End:   lsp.Position{Line: 1, Character: 7},
+			},
+			Message:  `field "feild" does not exist; did you mean "field"?`,
+			Severity: lsp.Warning,
+			Code:     DiagnosticUnknownField,
+		},
+	}
+
+	got := p.unknownFieldDiagnostics("file.jsonnet", source)
+	assert.Equal(t, expected, got)
+}
@@ -3,6 +3,7 @@ package lexical
 import (
 	"context"
 
+	"github.com/tminor/jsonnet-language-server/pkg/analysis/lexical/token"
 	"github.com/tminor/jsonnet-language-server/pkg/config"
 	"github.com/sourcegraph/jsonrpc2"
 )
@@ -13,7 +14,7 @@ type fakeDocumentProcessor struct {
 
 var _ DocumentProcessor = (*fakeDocumentProcessor)(nil)
 
-func (dp *fakeDocumentProcessor) Process(td config.TextDocument, conn RPCConn) error {
+func (dp *fakeDocumentProcessor) Process(ctx context.Context, td config.TextDocument, conn RPCConn) error {
 	return dp.processErr
 }
 
@@ -36,3 +37,18 @@ type fakeRPCConn struct {
 func (c *fakeRPCConn) Notify(ctx context.Context, method string, params interface{}, opts ...jsonrpc2.CallOption) error {
 	return c.notifyErr
 }
+
+type fakePerformDiagnosticsConfig struct {
+	libPaths  []string
+	nodeCache *token.NodeCache
+}
+
+var _ PerformDiagnosticsConfig = (*fakePerformDiagnosticsConfig)(nil)
+
+func (c *fakePerformDiagnosticsConfig) JsonnetLibPaths() []string {
+	return c.libPaths
+}
+
+func (c *fakePerformDiagnosticsConfig) NodeCache() *token.NodeCache {
+	return c.nodeCache
+}
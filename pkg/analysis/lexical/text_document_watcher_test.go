@@ -1,10 +1,12 @@
 package lexical
 
 import (
+	"context"
 	"testing"
 
 	"github.com/tminor/jsonnet-language-server/pkg/config"
 	"github.com/tminor/jsonnet-language-server/pkg/lsp"
+	"github.com/stretchr/testify/require"
 )
 
 func TestTextDocumentWatcher_watch(t *testing.T) {
@@ -21,5 +23,6 @@ func TestTextDocumentWatcher_watch(t *testing.T) {
 		URI:  "file:///file.jsonnet",
 	})
 
-	c.watchFn(td)
+	err := c.watchFn(context.Background(), td)
+	require.NoError(t, err)
 }
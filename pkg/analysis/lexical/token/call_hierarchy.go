@@ -0,0 +1,352 @@
+package token
+
+import (
+	"io/ioutil"
+	"path/filepath"
+	"sort"
+
+	"github.com/tminor/jsonnet-language-server/pkg/lsp"
+	jpos "github.com/tminor/jsonnet-language-server/pkg/util/position"
+	"github.com/google/go-jsonnet/ast"
+)
+
+// CallHierarchyItem is a function in a call hierarchy. Functions are locals
+// and object fields whose values are functions. Calls which aren't made in
+// a function are made by the file.
+type CallHierarchyItem struct {
+	Name   string
+	Kind   lsp.SymbolKind
+	Detail string
+	// Location is the range from the function's name to the end of its body.
+	Location jpos.Location
+	// SelectionRange is the range of the function's name.
+	SelectionRange jpos.Range
+}
+
+// Declaration is the location of the item's name. It identifies the item.
+func (item *CallHierarchyItem) Declaration() jpos.Location {
+	return jpos.NewLocation(item.Location.URI(), item.SelectionRange)
+}
+
+// CallHierarchyCall is an item which calls or is called by another item.
+// Ranges are the ranges of the called functions' names in the caller.
+type CallHierarchyCall struct {
+	Item   CallHierarchyItem
+	Ranges []jpos.Range
+}
+
+// PrepareCallHierarchy returns the function declared or called at a
+// position.
+func PrepareCallHierarchy(filename, source string, pos jpos.Position, nodeCache *NodeCache, libPaths []string) ([]CallHierarchyItem, error) {
+	ch := newCallHierarchy(nodeCache, libPaths)
+
+	cg, err := ch.graph(filename, source)
+	if err != nil {
+		return nil, err
+	}
+
+	decl, ok := cg.ri.at(pos)
+	if !ok {
+		return nil, nil
+	}
+
+	item, ok := ch.function(cg.ri.declarationsOf(decl))
+	if !ok {
+		return nil, nil
+	}
+
+	return []CallHierarchyItem{item}, nil
+}
+
+// IncomingCalls returns the calls to an item made in source. Calls are
+// grouped by the function making them.
+func IncomingCalls(filename, source string, item CallHierarchyItem, nodeCache *NodeCache, libPaths []string) ([]CallHierarchyCall, error) {
+	ch := newCallHierarchy(nodeCache, libPaths)
+
+	cg, err := ch.graph(filename, source)
+	if err != nil {
+		return nil, err
+	}
+
+	refs := make(map[jpos.Location]bool)
+	for _, ref := range cg.ri.referencesTo(item.Declaration()) {
+		refs[ref] = true
+	}
+
+	var calls callList
+	for _, c := range cg.calls {
+		if refs[jpos.NewLocation(filename, c.r)] {
+			calls.add(c.caller, c.r)
+		}
+	}
+
+	return calls.sorted(), nil
+}
+
+// OutgoingCalls returns the calls made by an item. Calls are grouped by the
+// function being called. Calls to functions which can't be resolved, e.g.
+// functions in the standard library, are skipped.
+func OutgoingCalls(filename, source string, item CallHierarchyItem, nodeCache *NodeCache, libPaths []string) ([]CallHierarchyCall, error) {
+	ch := newCallHierarchy(nodeCache, libPaths)
+
+	cg, err := ch.graph(filename, source)
+	if err != nil {
+		return nil, err
+	}
+
+	decl := item.Declaration()
+
+	var calls callList
+	for _, c := range cg.calls {
+		if c.caller.Declaration() != decl {
+			continue
+		}
+
+		callee, ok := cg.ri.at(c.r.Start)
+		if !ok {
+			continue
+		}
+
+		calleeItem, ok := ch.function(cg.ri.declarationsOf(callee))
+		if !ok {
+			continue
+		}
+
+		calls.add(calleeItem, c.r)
+	}
+
+	return calls.sorted(), nil
+}
+
+// call is a call to a function. r is the range of the called function's
+// name.
+type call struct {
+	caller CallHierarchyItem
+	r      jpos.Range
+}
+
+// callGraph is the functions declared in a file and the calls made in it.
+type callGraph struct {
+	filename  string
+	ri        *referenceIndex
+	file      CallHierarchyItem
+	functions map[*ast.Function]CallHierarchyItem
+	decls     map[jpos.Location]CallHierarchyItem
+	calls     []call
+}
+
+func newCallGraph(filename, source string, nodeCache *NodeCache, libPaths []string) (*callGraph, error) {
+	node, err := ReadSource(filename, source, nil)
+	if err != nil {
+		return nil, err
+	}
+
+	sg := scanScope(node, nodeCache)
+
+	cg := &callGraph{
+		filename: filename,
+		ri:       newReferenceIndex(filename, sg, libPaths, nodeCache),
+		file: CallHierarchyItem{
+			Name:           filepath.Base(filename),
+			Kind:           lsp.SKFile,
+			Location:       jpos.NewLocation(filename, fileRange(source)),
+			SelectionRange: jpos.NewRangeFromCoords(1, 1, 1, 1),
+		},
+		functions: make(map[*ast.Function]CallHierarchyItem),
+		decls:     make(map[jpos.Location]CallHierarchyItem),
+	}
+
+	for n := range sg.parents {
+		switch n := n.(type) {
+		case *ast.Local:
+			for _, bind := range n.Binds {
+				cg.addFunction(string(bind.Variable), lsp.SKFunction, bind.VarLoc, bind.Body)
+			}
+		case *ast.DesugaredObject:
+			for _, field := range n.Fields {
+				name, err := fieldName(field)
+				if err != nil {
+					continue
+				}
+
+				cg.addFunction(name, lsp.SKMethod, n.FieldLocs[name], fieldBody(field))
+			}
+		}
+	}
+
+	for n := range sg.parents {
+		apply, ok := n.(*ast.Apply)
+		if !ok {
+			continue
+		}
+
+		r, ok := calledName(apply)
+		if !ok {
+			continue
+		}
+
+		cg.calls = append(cg.calls, call{
+			caller: cg.caller(sg, apply),
+			r:      jpos.FromJsonnetRange(r),
+		})
+	}
+
+	return cg, nil
+}
+
+// addFunction adds a function declared by a local or a field.
+func (cg *callGraph) addFunction(name string, kind lsp.SymbolKind, loc ast.LocationRange, body ast.Node) {
+	fn, ok := body.(*ast.Function)
+	if !ok || loc.Begin.Line == 0 {
+		return
+	}
+
+	selectionRange := jpos.FromJsonnetRange(loc)
+
+	item := CallHierarchyItem{
+		Name:           name,
+		Kind:           kind,
+		Detail:         functionDetail(fn),
+		Location:       jpos.NewLocation(cg.filename, declarationRange(selectionRange, fn)),
+		SelectionRange: selectionRange,
+	}
+
+	cg.functions[fn] = item
+	cg.decls[item.Declaration()] = item
+}
+
+// caller returns the function a node is in. Nodes outside of functions are
+// in the file.
+func (cg *callGraph) caller(sg *scopeGraph, n ast.Node) CallHierarchyItem {
+	for cur := sg.parentOf(n); cur != nil; cur = sg.parentOf(cur) {
+		if fn, ok := cur.(*ast.Function); ok {
+			if item, ok := cg.functions[fn]; ok {
+				return item
+			}
+		}
+	}
+
+	return cg.file
+}
+
+// calledName returns the range of the name of the function an apply calls,
+// e.g. `f` in `f(x)` or `b` in `a.b(x)`.
+func calledName(apply *ast.Apply) (ast.LocationRange, bool) {
+	switch target := apply.Target.(type) {
+	case *ast.Var:
+		r := *target.Loc()
+		return r, r.Begin.Line != 0
+	case *ast.Index:
+		name, ok := target.Index.(*ast.LiteralString)
+		if !ok || target.Loc().End.Line == 0 {
+			return ast.LocationRange{}, false
+		}
+
+		return indexedNameRange(*target.Loc(), name)
+	case *ast.SuperIndex:
+		name, ok := target.Index.(*ast.LiteralString)
+		if !ok || target.Loc().End.Line == 0 {
+			return ast.LocationRange{}, false
+		}
+
+		return superIndexNameRange(*target.Loc(), name)
+	default:
+		return ast.LocationRange{}, false
+	}
+}
+
+// callHierarchy finds functions in files. Files other than the current
+// file are read from disk once.
+type callHierarchy struct {
+	nodeCache *NodeCache
+	libPaths  []string
+	graphs    map[string]*callGraph
+}
+
+func newCallHierarchy(nodeCache *NodeCache, libPaths []string) *callHierarchy {
+	return &callHierarchy{
+		nodeCache: nodeCache,
+		libPaths:  libPaths,
+		graphs:    make(map[string]*callGraph),
+	}
+}
+
+// graph returns the call graph for a file.
+func (ch *callHierarchy) graph(filename, source string) (*callGraph, error) {
+	if cg, ok := ch.graphs[filename]; ok {
+		return cg, nil
+	}
+
+	cg, err := newCallGraph(filename, source, ch.nodeCache, ch.libPaths)
+	if err != nil {
+		return nil, err
+	}
+
+	ch.graphs[filename] = cg
+
+	return cg, nil
+}
+
+// function returns the first declaration which declares a function.
+func (ch *callHierarchy) function(decls []jpos.Location) (CallHierarchyItem, bool) {
+	for i := range decls {
+		filename := decls[i].URI()
+
+		cg, ok := ch.graphs[filename]
+		if !ok {
+			/* #nosec */
+			source, err := ioutil.ReadFile(filename)
+			if err != nil {
+				continue
+			}
+
+			cg, err = ch.graph(filename, string(source))
+			if err != nil {
+				continue
+			}
+		}
+
+		if item, ok := cg.decls[decls[i]]; ok {
+			return item, true
+		}
+	}
+
+	return CallHierarchyItem{}, false
+}
+
+// callList groups call ranges by item.
+type callList struct {
+	calls []CallHierarchyCall
+}
+
+func (cl *callList) add(item CallHierarchyItem, r jpos.Range) {
+	decl := item.Declaration()
+	for i := range cl.calls {
+		if cl.calls[i].Item.Declaration() == decl {
+			cl.calls[i].Ranges = append(cl.calls[i].Ranges, r)
+			return
+		}
+	}
+
+	cl.calls = append(cl.calls, CallHierarchyCall{Item: item, Ranges: []jpos.Range{r}})
+}
+
+// sorted returns the calls sorted by where they are first made.
+func (cl *callList) sorted() []CallHierarchyCall {
+	for _, c := range cl.calls {
+		sortRanges(c.Ranges)
+	}
+
+	sort.Slice(cl.calls, func(i, j int) bool {
+		a, b := cl.calls[i].Ranges[0].Start, cl.calls[j].Ranges[0].Start
+		return locationBefore(a.ToJsonnet(), b.ToJsonnet())
+	})
+
+	return cl.calls
+}
+
+func sortRanges(ranges []jpos.Range) {
+	sort.Slice(ranges, func(i, j int) bool {
+		return locationBefore(ranges[i].Start.ToJsonnet(), ranges[j].Start.ToJsonnet())
+	})
+}
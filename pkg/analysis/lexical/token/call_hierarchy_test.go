@@ -0,0 +1,132 @@
+package token
+
+import (
+	"testing"
+
+	"github.com/tminor/jsonnet-language-server/pkg/lsp"
+	jpos "github.com/tminor/jsonnet-language-server/pkg/util/position"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const callHierarchySource = `local add(a, b) = a + b;
+local twice(x) = add(x, x);
+{
+  sum: add(1, 2),
+  double(x):: twice(x),
+  quad(x):: self.double(self.double(x)),
+}`
+
+func callHierarchyItem(name string, kind lsp.SymbolKind, detail string, location, selectionRange jpos.Range) CallHierarchyItem {
+	return CallHierarchyItem{
+		Name:           name,
+		Kind:           kind,
+		Detail:         detail,
+		Location:       jpos.NewLocation("file.jsonnet", location),
+		SelectionRange: selectionRange,
+	}
+}
+
+var (
+	callHierarchyFile = callHierarchyItem("file.jsonnet", lsp.SKFile, "",
+		jpos.NewRangeFromCoords(1, 1, 7, 2), jpos.NewRangeFromCoords(1, 1, 1, 1))
+	callHierarchyAdd = callHierarchyItem("add", lsp.SKFunction, "function(a, b)",
+		jpos.NewRangeFromCoords(1, 7, 1, 24), jpos.NewRangeFromCoords(1, 7, 1, 10))
+	callHierarchyTwice = callHierarchyItem("twice", lsp.SKFunction, "function(x)",
+		jpos.NewRangeFromCoords(2, 7, 2, 27), jpos.NewRangeFromCoords(2, 7, 2, 12))
+	callHierarchyDouble = callHierarchyItem("double", lsp.SKMethod, "function(x)",
+		jpos.NewRangeFromCoords(5, 3, 5, 23), jpos.NewRangeFromCoords(5, 3, 5, 9))
+	callHierarchyQuad = callHierarchyItem("quad", lsp.SKMethod, "function(x)",
+		jpos.NewRangeFromCoords(6, 3, 6, 40), jpos.NewRangeFromCoords(6, 3, 6, 7))
+)
+
+func TestPrepareCallHierarchy(t *testing.T) {
+	cases := []struct {
+		name     string
+		pos      jpos.Position
+		expected []CallHierarchyItem
+	}{
+		{
+			name:     "local declaration",
+			pos:      jpos.New(1, 8),
+			expected: []CallHierarchyItem{callHierarchyAdd},
+		},
+		{
+			name:     "local call",
+			pos:      jpos.New(4, 8),
+			expected: []CallHierarchyItem{callHierarchyAdd},
+		},
+		{
+			name:     "method call",
+			pos:      jpos.New(6, 18),
+			expected: []CallHierarchyItem{callHierarchyDouble},
+		},
+		{
+			name: "not a function",
+			pos:  jpos.New(4, 3),
+		},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			got, err := PrepareCallHierarchy("file.jsonnet", callHierarchySource, tc.pos, NewNodeCache(), nil)
+			require.NoError(t, err)
+
+			assert.Equal(t, tc.expected, got)
+		})
+	}
+}
+
+func TestIncomingCalls(t *testing.T) {
+	expected := []CallHierarchyCall{
+		{Item: callHierarchyTwice, Ranges: []jpos.Range{jpos.NewRangeFromCoords(2, 18, 2, 21)}},
+		{Item: callHierarchyFile, Ranges: []jpos.Range{jpos.NewRangeFromCoords(4, 8, 4, 11)}},
+	}
+
+	got, err := IncomingCalls("file.jsonnet", callHierarchySource, callHierarchyAdd, NewNodeCache(), nil)
+	require.NoError(t, err)
+
+	assert.Equal(t, expected, got)
+}
+
+func TestOutgoingCalls(t *testing.T) {
+	cases := []struct {
+		name     string
+		item     CallHierarchyItem
+		expected []CallHierarchyCall
+	}{
+		{
+			name: "method",
+			item: callHierarchyQuad,
+			expected: []CallHierarchyCall{
+				{
+					Item: callHierarchyDouble,
+					Ranges: []jpos.Range{
+						jpos.NewRangeFromCoords(6, 18, 6, 24),
+						jpos.NewRangeFromCoords(6, 30, 6, 36),
+					},
+				},
+			},
+		},
+		{
+			name: "file",
+			item: callHierarchyFile,
+			expected: []CallHierarchyCall{
+				{Item: callHierarchyAdd, Ranges: []jpos.Range{jpos.NewRangeFromCoords(4, 8, 4, 11)}},
+			},
+		},
+		{
+			name: "no calls",
+			item: callHierarchyAdd,
+		},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			got, err := OutgoingCalls("file.jsonnet", callHierarchySource, tc.item, NewNodeCache(), nil)
+			require.NoError(t, err)
+
+			assert.Equal(t, tc.expected, got)
+		})
+	}
+}
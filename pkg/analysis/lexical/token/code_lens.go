@@ -0,0 +1,112 @@
+package token
+
+import (
+	"bytes"
+	"fmt"
+	"path/filepath"
+	"strings"
+
+	jpos "github.com/tminor/jsonnet-language-server/pkg/util/position"
+	"github.com/google/go-jsonnet/ast"
+)
+
+// CodeLensKind is the kind of a code lens.
+type CodeLensKind int
+
+const (
+	// CodeLensReferences shows the references to a declaration.
+	CodeLensReferences CodeLensKind = iota
+	// CodeLensEvaluate evaluates a file or a field.
+	CodeLensEvaluate
+)
+
+// CodeLens is an action shown above a declaration.
+type CodeLens struct {
+	Kind  CodeLensKind
+	Range jpos.Range
+	// References are the references to the declaration for a references
+	// lens.
+	References []jpos.Location
+	// Path is the path of the field to evaluate for an evaluate lens. It is
+	// empty when the whole file is evaluated.
+	Path []string
+}
+
+// CodeLenses returns code lenses for a file. Top level locals and the fields
+// of the object the file evaluates to show how many times they are referenced
+// in the file. Files with a .jsonnet extension and fields which are
+// functions that can be called without arguments can be evaluated.
+func CodeLenses(filename, source string, nodeCache *NodeCache, libPaths []string) ([]CodeLens, error) {
+	node, err := ReadSource(filename, source, nil)
+	if err != nil {
+		return nil, err
+	}
+
+	sg := scanScope(node, nodeCache)
+	ri := newReferenceIndex(filename, sg, libPaths, nodeCache)
+
+	var lenses []CodeLens
+
+	if filepath.Ext(filename) == ".jsonnet" {
+		lenses = append(lenses, CodeLens{
+			Kind:  CodeLensEvaluate,
+			Range: jpos.NewRangeFromCoords(1, 1, 1, 1),
+		})
+	}
+
+	binds, body := topLevelBinds(node)
+	for _, bind := range binds {
+		lenses = append(lenses, CodeLens{
+			Kind:       CodeLensReferences,
+			Range:      jpos.FromJsonnetRange(bind.VarLoc),
+			References: ri.referencesTo(ri.location(bind.VarLoc)),
+		})
+	}
+
+	walkExportedFields(body, nil, 0, func(ef exportedField) {
+		r := jpos.FromJsonnetRange(ef.loc)
+
+		lenses = append(lenses, CodeLens{
+			Kind:       CodeLensReferences,
+			Range:      r,
+			References: ri.referencesTo(ri.location(ef.loc)),
+		})
+
+		if isCallableWithoutArguments(ef.body) {
+			lenses = append(lenses, CodeLens{
+				Kind:  CodeLensEvaluate,
+				Range: r,
+				Path:  ef.path(),
+			})
+		}
+	})
+
+	return lenses, nil
+}
+
+// isCallableWithoutArguments returns true if a node is a function without
+// required parameters.
+func isCallableWithoutArguments(n ast.Node) bool {
+	fn, ok := n.(*ast.Function)
+	return ok && len(fn.Parameters.Required) == 0
+}
+
+// Evaluate evaluates source and manifests it as JSON. If path isn't empty,
+// the field at path is evaluated instead. A field which is a function is
+// called without arguments.
+func Evaluate(filename, source string, path []string, config IdentifyConfig) (string, error) {
+	var accessor bytes.Buffer
+	for _, name := range path {
+		fmt.Fprintf(&accessor, "[%q]", name)
+	}
+
+	snippet := fmt.Sprintf("local __value = (\n%s\n)%s;\nif std.isFunction(__value) then __value() else __value\n",
+		source, accessor.String())
+
+	out, err := config.VM().EvaluateSnippet(filename, snippet)
+	if err != nil {
+		return "", err
+	}
+
+	return strings.TrimSpace(out), nil
+}
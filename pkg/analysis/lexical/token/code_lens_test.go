@@ -0,0 +1,113 @@
+package token
+
+import (
+	"testing"
+
+	jpos "github.com/tminor/jsonnet-language-server/pkg/util/position"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestCodeLenses(t *testing.T) {
+	source := "local a = 1;\n" +
+		"local f() = a;\n" +
+		"{\n" +
+		"  b: a + f(),\n" +
+		"  c():: self.b,\n" +
+		"  d: self.b,\n" +
+		"}"
+
+	loc := func(sl, sc, el, ec int) jpos.Location {
+		return jpos.NewLocation("file.jsonnet", jpos.NewRangeFromCoords(sl, sc, el, ec))
+	}
+
+	expected := []CodeLens{
+		{
+			Kind:  CodeLensEvaluate,
+			Range: jpos.NewRangeFromCoords(1, 1, 1, 1),
+		},
+		{
+			Kind:       CodeLensReferences,
+			Range:      jpos.NewRangeFromCoords(1, 7, 1, 8),
+			References: []jpos.Location{loc(2, 13, 2, 14), loc(4, 6, 4, 7)},
+		},
+		{
+			Kind:       CodeLensReferences,
+			Range:      jpos.NewRangeFromCoords(2, 7, 2, 8),
+			References: []jpos.Location{loc(4, 10, 4, 11)},
+		},
+		{
+			Kind:       CodeLensReferences,
+			Range:      jpos.NewRangeFromCoords(4, 3, 4, 4),
+			References: []jpos.Location{loc(5, 14, 5, 15), loc(6, 11, 6, 12)},
+		},
+		{
+			Kind:  CodeLensReferences,
+			Range: jpos.NewRangeFromCoords(5, 3, 5, 4),
+		},
+		{
+			Kind:  CodeLensEvaluate,
+			Range: jpos.NewRangeFromCoords(5, 3, 5, 4),
+			Path:  []string{"c"},
+		},
+		{
+			Kind:  CodeLensReferences,
+			Range: jpos.NewRangeFromCoords(6, 3, 6, 4),
+		},
+	}
+
+	got, err := CodeLenses("file.jsonnet", source, NewNodeCache(), nil)
+	require.NoError(t, err)
+
+	assert.Equal(t, expected, got)
+}
+
+func TestEvaluate(t *testing.T) {
+	cases := []struct {
+		name     string
+		source   string
+		path     []string
+		expected string
+		isErr    bool
+	}{
+		{
+			name:     "file",
+			source:   "local a = 1;\n{a: a}",
+			expected: "{\n   \"a\": 1\n}",
+		},
+		{
+			name:     "field",
+			source:   "{a: {b: 'x'}}",
+			path:     []string{"a", "b"},
+			expected: `"x"`,
+		},
+		{
+			name:     "function",
+			source:   "{f(x=1):: x + 1}",
+			path:     []string{"f"},
+			expected: "2",
+		},
+		{
+			name:   "missing field",
+			source: "{}",
+			path:   []string{"a"},
+			isErr:  true,
+		},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			config, err := NewIdentifyConfig("file.jsonnet")
+			require.NoError(t, err)
+
+			got, err := Evaluate("file.jsonnet", tc.source, tc.path, config)
+			if tc.isErr {
+				require.Error(t, err)
+				return
+			}
+
+			require.NoError(t, err)
+			assert.Equal(t, tc.expected, got)
+		})
+	}
+}
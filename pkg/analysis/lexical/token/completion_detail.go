@@ -0,0 +1,212 @@
+package token
+
+import (
+	"bytes"
+	"strings"
+
+	jpos "github.com/tminor/jsonnet-language-server/pkg/util/position"
+	"github.com/google/go-jsonnet/ast"
+	"github.com/ksonnet/ksonnet-lib/ksonnet-gen/printer"
+	"github.com/pkg/errors"
+)
+
+const (
+	// maxPreviewLines limits the size of value previews.
+	maxPreviewLines = 10
+)
+
+// CompletionDetail describes a completion item. It is expensive to compute,
+// so it is only created for the item the client resolves.
+type CompletionDetail struct {
+	// Detail is a short description of the item.
+	Detail string
+	// Documentation is the comment documenting the item's declaration.
+	Documentation string
+	// Value is a preview of the item's evaluated value.
+	Value string
+	// Location is where the item is declared. It is nil if the item was
+	// not declared in source, e.g. it is from an evaluated import.
+	Location *jpos.Location
+}
+
+// IndexFieldDetail describes a field of the expression being indexed at a
+// position. The position is immediately after the dot.
+func IndexFieldDetail(source string, pos jpos.Position, name string, nodeCache *NodeCache, config IdentifyConfig) (*CompletionDetail, error) {
+	node, sg, pi, err := findPartialIndex(source, pos, nodeCache, config)
+	if err != nil {
+		return nil, err
+	}
+
+	fields, err := indexFields(node, sg, pi, nodeCache, config)
+	if err != nil {
+		return nil, err
+	}
+
+	field, ok := lookupField(fields, name)
+	if !ok {
+		return nil, errors.Errorf("index does not have field %q", name)
+	}
+
+	cd := describeField(field, config.path, source)
+
+	if !field.IsFunction() {
+		index := &ast.Index{
+			Target: pi.Target,
+			Index:  makeStr(name),
+		}
+
+		if value, err := evaluateInScope(node, pi.Target, index, nodeCache, config); err == nil {
+			cd.Value = value
+		}
+	}
+
+	return cd, nil
+}
+
+// FieldCandidateDetail describes a field which can be set in an object
+// literal at a position.
+func FieldCandidateDetail(source string, pos jpos.Position, name string, nodeCache *NodeCache, config IdentifyConfig) (*CompletionDetail, error) {
+	fc, err := FieldCandidates(config.path, source, pos, config.jsonnetLibPaths, nodeCache)
+	if err != nil {
+		return nil, err
+	}
+
+	if fc == nil {
+		return nil, errors.Errorf("no field candidates at %s", pos.String())
+	}
+
+	field, ok := lookupField(fc.Fields, name)
+	if !ok {
+		return nil, errors.Errorf("object does not have field %q", name)
+	}
+
+	return describeField(field, config.path, source), nil
+}
+
+// VariableDetail describes a variable which is in scope at a position.
+func VariableDetail(source string, pos jpos.Position, name string, nodeCache *NodeCache, config IdentifyConfig) (*CompletionDetail, error) {
+	node, err := ReadSource(config.path, source, nil)
+	if err != nil {
+		return nil, err
+	}
+
+	found, err := locateNode(node, pos)
+	if err != nil {
+		return nil, errors.Wrap(err, "locate node at position")
+	}
+
+	cd := &CompletionDetail{
+		Detail: name,
+	}
+
+	sg := scanScope(node, nodeCache)
+	if s, ok := sg.idScopes[found]; ok {
+		if loc, ok := s.idMap[ast.Identifier(name)]; ok {
+			cd.Location = &loc
+
+			doc, err := DocComment(config.path, source, loc.ToJsonnet().Begin)
+			if err == nil {
+				cd.Documentation = doc
+			}
+		}
+
+		if decl := s.declMap[ast.Identifier(name)]; decl != nil {
+			cd.Detail = describeNode(decl)
+		}
+	}
+
+	v := &ast.Var{Id: ast.Identifier(name)}
+	if value, err := evaluateInScope(node, found, v, nodeCache, config); err == nil {
+		cd.Value = value
+	}
+
+	return cd, nil
+}
+
+func lookupField(fields []Field, name string) (Field, bool) {
+	for _, field := range fields {
+		if field.Name == name {
+			return field, true
+		}
+	}
+
+	return Field{}, false
+}
+
+// describeField describes a field. The field's declaration is read from disk
+// unless it is in the current file.
+func describeField(field Field, filename, source string) *CompletionDetail {
+	cd := &CompletionDetail{
+		Detail: describeNode(field.Node),
+	}
+
+	if field.IsHidden() {
+		cd.Detail += " (hidden)"
+	}
+
+	uri := field.Location.URI()
+	if uri == "" {
+		return cd
+	}
+
+	loc := field.Location
+	cd.Location = &loc
+
+	source, err := sourceFor(uri, filename, source)
+	if err != nil {
+		return cd
+	}
+
+	doc, err := DocComment(uri, source, loc.ToJsonnet().Begin)
+	if err == nil {
+		cd.Documentation = doc
+	}
+
+	return cd
+}
+
+// describeNode is a short description of a node. Objects are not described
+// field by field since they can be large.
+func describeNode(node ast.Node) string {
+	f := Field{Node: node}
+	return f.Detail()
+}
+
+// evaluateInScope evaluates expr using the variables in scope at a node in
+// root.
+func evaluateInScope(root, at, expr ast.Node, nodeCache *NodeCache, config IdentifyConfig) (string, error) {
+	es, err := eval(root, at, nodeCache)
+	if err != nil {
+		return "", err
+	}
+
+	scope := newScope(nodeCache)
+	scope.addEvalScope(es)
+
+	stub, err := buildEvalStub(expr, scope)
+	if err != nil {
+		return "", err
+	}
+
+	evaluated, err := evaluateNode(stub, config.VM())
+	if err != nil {
+		return "", err
+	}
+
+	return previewNode(evaluated)
+}
+
+// previewNode prints a node. Long output is truncated.
+func previewNode(node ast.Node) (string, error) {
+	var buf bytes.Buffer
+	if err := printer.Fprint(&buf, node); err != nil {
+		return "", err
+	}
+
+	lines := strings.Split(strings.TrimSpace(buf.String()), "\n")
+	if len(lines) > maxPreviewLines {
+		lines = append(lines[:maxPreviewLines], "...")
+	}
+
+	return strings.Join(lines, "\n"), nil
+}
@@ -0,0 +1,61 @@
+package token
+
+import (
+	"testing"
+
+	jpos "github.com/tminor/jsonnet-language-server/pkg/util/position"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestIndexFieldDetail(t *testing.T) {
+	source := "local o = {\n  // the answer\n  a: 42,\n  b:: 'b',\n};\no."
+
+	config, err := NewIdentifyConfig("file.jsonnet")
+	require.NoError(t, err)
+
+	cd, err := IndexFieldDetail(source, jpos.New(6, 3), "a", NewNodeCache(), config)
+	require.NoError(t, err)
+
+	assert.Equal(t, "(number) 42", cd.Detail)
+	assert.Equal(t, "the answer", cd.Documentation)
+	assert.Contains(t, cd.Value, "42")
+	require.NotNil(t, cd.Location)
+	assert.Equal(t, jpos.New(3, 3), cd.Location.Range().Start)
+
+	cd, err = IndexFieldDetail(source, jpos.New(6, 3), "b", NewNodeCache(), config)
+	require.NoError(t, err)
+	assert.Contains(t, cd.Detail, "(hidden)")
+
+	_, err = IndexFieldDetail(source, jpos.New(6, 3), "c", NewNodeCache(), config)
+	require.Error(t, err)
+}
+
+func TestFieldCandidateDetail(t *testing.T) {
+	source := "local base = {\n  // the a field\n  a: 1,\n};\nbase + { }"
+
+	config, err := NewIdentifyConfig("file.jsonnet")
+	require.NoError(t, err)
+
+	cd, err := FieldCandidateDetail(source, jpos.New(5, 10), "a", NewNodeCache(), config)
+	require.NoError(t, err)
+
+	assert.Equal(t, "(number) 1", cd.Detail)
+	assert.Equal(t, "the a field", cd.Documentation)
+}
+
+func TestVariableDetail(t *testing.T) {
+	source := "// the answer\nlocal a = 42;\na"
+
+	config, err := NewIdentifyConfig("file.jsonnet")
+	require.NoError(t, err)
+
+	cd, err := VariableDetail(source, jpos.New(3, 1), "a", NewNodeCache(), config)
+	require.NoError(t, err)
+
+	assert.Equal(t, "(number) 42", cd.Detail)
+	assert.Equal(t, "the answer", cd.Documentation)
+	assert.Contains(t, cd.Value, "42")
+	require.NotNil(t, cd.Location)
+	assert.Equal(t, jpos.New(2, 7), cd.Location.Range().Start)
+}
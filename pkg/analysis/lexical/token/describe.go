@@ -0,0 +1,264 @@
+package token
+
+import (
+	"bytes"
+	"io/ioutil"
+	"strings"
+
+	"github.com/tminor/jsonnet-language-server/pkg/analysis/lexical/astext"
+	jpos "github.com/tminor/jsonnet-language-server/pkg/util/position"
+	jsonnet "github.com/google/go-jsonnet"
+	"github.com/google/go-jsonnet/ast"
+	"github.com/ksonnet/ksonnet-lib/ksonnet-gen/printer"
+	"github.com/pkg/errors"
+)
+
+const (
+	// maxValueLines limits the size of manifested values.
+	maxValueLines = 20
+)
+
+// Description describes what is at a position.
+type Description struct {
+	// Static is a description of the node found without evaluating it.
+	Static string
+	// Declaration is the source line where the node is declared.
+	Declaration string
+	// Documentation is the comment documenting the declaration.
+	Documentation string
+	// Location is where the node is declared. It is nil if the declaration
+	// could not be found.
+	Location *jpos.Location
+	// Value is the node manifested as JSON. It is blank if the node could
+	// not be evaluated.
+	Value string
+	// Import describes how an import was resolved if the node is an import.
+	Import *ImportDescription
+	// Object describes the object if the node is `self`, `super` or `$`.
+	Object *ObjectDescription
+	// Provenance is the definitions of a field in the objects it is
+	// composed from if the field is defined in more than one object
+	// composed with `+`. The merged value is Value.
+	Provenance []FieldDefinition
+}
+
+// IsEmpty returns true if nothing was described.
+func (d *Description) IsEmpty() bool {
+	return d.Static == "" && d.Declaration == "" && d.Value == "" && d.Import == nil && d.Object == nil
+}
+
+// Describe describes what is at a position. Variables and fields are
+// described by their declaration. The value is evaluated with the VM, but
+// if evaluation fails, the static description is still returned.
+func Describe(source string, pos jpos.Position, nodeCache *NodeCache, config IdentifyConfig) (*Description, error) {
+	node, err := ReadSource(config.path, source, nil)
+	if err != nil {
+		return nil, err
+	}
+
+	found, err := locateNode(node, pos)
+	if err != nil {
+		return nil, errors.Wrap(err, "locate node at position")
+	}
+
+	switch found.(type) {
+	case nil, *astext.Partial:
+		return &Description{}, nil
+	}
+
+	switch n := found.(type) {
+	case *ast.Import:
+		return describeImport(source, pos, n.File.Value, false, nodeCache, config)
+	case *ast.ImportStr:
+		return describeImport(source, pos, n.File.Value, true, nodeCache, config)
+	}
+
+	sg := scanScope(node, nodeCache)
+
+	switch found.(type) {
+	case *ast.Self, *ast.SuperIndex:
+		return describeObject(sg, found, nodeCache, config)
+	}
+
+	if isDollar(found) {
+		return describeObject(sg, found, nodeCache, config)
+	}
+
+	d := &Description{}
+
+	target := found
+	if decl, loc := declarationOf(sg, found, pos, nodeCache, config); loc != nil {
+		d.Location = loc
+
+		if decl != nil {
+			target = decl
+		}
+
+		declSource, err := sourceFor(loc.URI(), config.path, source)
+		if err == nil {
+			r := loc.Range()
+			d.Declaration = sourceLine(declSource, r.Start.Line())
+
+			if doc, err := DocComment(loc.URI(), declSource, loc.ToJsonnet().Begin); err == nil {
+				d.Documentation = doc
+			}
+		}
+	}
+
+	if target != nil {
+		d.Static = astext.TokenName(target)
+	}
+
+	d.Provenance = provenanceOf(sg, found, pos, nodeCache, config)
+
+	// a field name can't be evaluated without its object.
+	if _, isObject := found.(*ast.DesugaredObject); isObject && d.Location != nil {
+		return d, nil
+	}
+
+	if value, err := manifestInScope(node, found, nodeCache, config); err == nil {
+		d.Value = value
+	}
+
+	return d, nil
+}
+
+func describeImport(source string, pos jpos.Position, name string, isImportStr bool, nodeCache *NodeCache, config IdentifyConfig) (*Description, error) {
+	id, err := DescribeImport(config.path, name, isImportStr, config.jsonnetLibPaths, nodeCache)
+	if err != nil {
+		return nil, err
+	}
+
+	return &Description{
+		Declaration: sourceLine(source, pos.Line()),
+		Import:      id,
+	}, nil
+}
+
+func describeObject(sg *scopeGraph, n ast.Node, nodeCache *NodeCache, config IdentifyConfig) (*Description, error) {
+	od, err := describeKeyword(sg, n, nodeCache, config)
+	if err != nil {
+		return nil, err
+	}
+
+	return &Description{
+		Static: od.Keyword,
+		Object: od,
+	}, nil
+}
+
+// declarationOf finds the declaration of a node. It returns the declared
+// value, if it is known, and the location of the declaration.
+func declarationOf(sg *scopeGraph, n ast.Node, pos jpos.Position, nodeCache *NodeCache, config IdentifyConfig) (ast.Node, *jpos.Location) {
+	switch n := n.(type) {
+	case *ast.Var:
+		s, ok := sg.idScopes[n]
+		if !ok {
+			return nil, nil
+		}
+
+		loc, ok := s.idMap[n.Id]
+		if !ok {
+			return nil, nil
+		}
+
+		return s.declMap[n.Id], &loc
+	case *ast.Index:
+		name, ok := n.Index.(*ast.LiteralString)
+		if !ok {
+			return nil, nil
+		}
+
+		or := newObjectResolver(config.path, sg, config.jsonnetLibPaths, nodeCache)
+		layers, err := or.layers(sg, n.Target)
+		if err != nil {
+			return nil, nil
+		}
+
+		return fieldDeclaration(or.fields(layers), name.Value)
+	case *ast.DesugaredObject:
+		// the position is in a field name.
+		name, _, err := fieldNameAt(n, pos)
+		if err != nil {
+			return nil, nil
+		}
+
+		layer := objectLayer{object: n, graph: sg}
+		return fieldDeclaration(layer.fields(), name)
+	default:
+		return nil, nil
+	}
+}
+
+func fieldDeclaration(fields []Field, name string) (ast.Node, *jpos.Location) {
+	field, ok := lookupField(fields, name)
+	if !ok || field.Location.URI() == "" {
+		return nil, nil
+	}
+
+	return field.Node, &field.Location
+}
+
+// sourceFor returns the source of filename. The current file's source may not
+// have been saved, so it is used rather than reading from disk.
+func sourceFor(filename, current, source string) (string, error) {
+	if filename == current {
+		return source, nil
+	}
+
+	data, err := ioutil.ReadFile(filename)
+	if err != nil {
+		return "", err
+	}
+
+	return string(data), nil
+}
+
+// sourceLine returns a line from source without surrounding whitespace.
+func sourceLine(source string, line int) string {
+	lines := strings.Split(source, "\n")
+	if line < 1 || line > len(lines) {
+		return ""
+	}
+
+	return strings.TrimSpace(lines[line-1])
+}
+
+// manifestInScope manifests a node as JSON using the variables in scope.
+func manifestInScope(root, n ast.Node, nodeCache *NodeCache, config IdentifyConfig) (string, error) {
+	es, err := eval(root, n, nodeCache)
+	if err != nil {
+		return "", err
+	}
+
+	scope := newScope(nodeCache)
+	scope.addEvalScope(es)
+
+	stub, err := buildEvalStub(n, scope)
+	if err != nil {
+		return "", err
+	}
+
+	return manifestNode(stub, config.VM())
+}
+
+// manifestNode evaluates a node and manifests it as JSON. Long output is
+// truncated.
+func manifestNode(node ast.Node, vm *jsonnet.VM) (string, error) {
+	var buf bytes.Buffer
+	if err := printer.Fprint(&buf, node); err != nil {
+		return "", err
+	}
+
+	out, err := vm.EvaluateSnippet("snippet.jsonnet", buf.String())
+	if err != nil {
+		return "", err
+	}
+
+	lines := strings.Split(strings.TrimSpace(out), "\n")
+	if len(lines) > maxValueLines {
+		lines = append(lines[:maxValueLines], "...")
+	}
+
+	return strings.Join(lines, "\n"), nil
+}
@@ -0,0 +1,156 @@
+package token
+
+import (
+	"testing"
+
+	jpos "github.com/tminor/jsonnet-language-server/pkg/util/position"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestDescribe(t *testing.T) {
+	cases := []struct {
+		name          string
+		source        string
+		pos           jpos.Position
+		static        string
+		declaration   string
+		documentation string
+		location      *jpos.Position
+		value         string
+	}{
+		{
+			name:          "variable",
+			source:        "// the answer\nlocal a = 42;\na",
+			pos:           jpos.New(3, 1),
+			static:        "(number) 42",
+			declaration:   "local a = 42;",
+			documentation: "the answer",
+			location:      positionPtr(jpos.New(2, 7)),
+			value:         "42",
+		},
+		{
+			name:          "field",
+			source:        "local o = {\n  // field a\n  a: 'a',\n};\no.a",
+			pos:           jpos.New(5, 3),
+			static:        `(string) "a"`,
+			declaration:   "a: 'a',",
+			documentation: "field a",
+			location:      positionPtr(jpos.New(3, 3)),
+			value:         `"a"`,
+		},
+		{
+			name:        "function can't be evaluated",
+			source:      "local f(x) = x;\nf",
+			pos:         jpos.New(2, 1),
+			static:      "(function)",
+			declaration: "local f(x) = x;",
+			location:    positionPtr(jpos.New(1, 7)),
+		},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			config, err := NewIdentifyConfig("file.jsonnet")
+			require.NoError(t, err)
+
+			d, err := Describe(tc.source, tc.pos, NewNodeCache(), config)
+			require.NoError(t, err)
+
+			assert.Equal(t, tc.static, d.Static)
+			assert.Equal(t, tc.declaration, d.Declaration)
+			assert.Equal(t, tc.documentation, d.Documentation)
+			assert.Equal(t, tc.value, d.Value)
+
+			if tc.location == nil {
+				assert.Nil(t, d.Location)
+				return
+			}
+
+			require.NotNil(t, d.Location)
+			assert.Equal(t, *tc.location, d.Location.Range().Start)
+		})
+	}
+}
+
+func positionPtr(p jpos.Position) *jpos.Position {
+	return &p
+}
+
+func TestDescribe_object(t *testing.T) {
+	cases := []struct {
+		name     string
+		source   string
+		pos      jpos.Position
+		keyword  string
+		location jpos.Position
+		fields   []string
+		chain    []jpos.Position
+	}{
+		{
+			name:     "self",
+			source:   "{a: 1, b:: self.a}",
+			pos:      jpos.New(1, 13),
+			keyword:  "self",
+			location: jpos.New(1, 1),
+			fields:   []string{"a", "b"},
+			chain:    []jpos.Position{jpos.New(1, 1)},
+		},
+		{
+			name:     "self in mixin",
+			source:   "{a: 1} + {b: self.a}",
+			pos:      jpos.New(1, 15),
+			keyword:  "self",
+			location: jpos.New(1, 10),
+			fields:   []string{"a", "b"},
+			chain:    []jpos.Position{jpos.New(1, 1), jpos.New(1, 10)},
+		},
+		{
+			name:     "super",
+			source:   "{a: 1} + {b:: 2} + {a: super.a}",
+			pos:      jpos.New(1, 24),
+			keyword:  "super",
+			location: jpos.New(1, 20),
+			fields:   []string{"a", "b"},
+			chain:    []jpos.Position{jpos.New(1, 1), jpos.New(1, 10)},
+		},
+		{
+			name:     "dollar",
+			source:   "{a: 1, b: {c: $.a}}",
+			pos:      jpos.New(1, 15),
+			keyword:  "$",
+			location: jpos.New(1, 1),
+			fields:   []string{"a", "b"},
+			chain:    []jpos.Position{jpos.New(1, 1)},
+		},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			config, err := NewIdentifyConfig("file.jsonnet")
+			require.NoError(t, err)
+
+			d, err := Describe(tc.source, tc.pos, NewNodeCache(), config)
+			require.NoError(t, err)
+
+			require.NotNil(t, d.Object)
+			assert.Equal(t, tc.keyword, d.Static)
+			assert.Equal(t, tc.keyword, d.Object.Keyword)
+
+			require.NotNil(t, d.Object.Location)
+			assert.Equal(t, tc.location, d.Object.Location.Range().Start)
+
+			var fields []string
+			for _, field := range d.Object.Fields {
+				fields = append(fields, field.Name)
+			}
+			assert.Equal(t, tc.fields, fields)
+
+			var chain []jpos.Position
+			for _, loc := range d.Object.Chain {
+				chain = append(chain, loc.Range().Start)
+			}
+			assert.Equal(t, tc.chain, chain)
+		})
+	}
+}
@@ -640,9 +640,15 @@ func desugar(astPtr *ast.Node, objLevel int) (err error) {
 	case *ast.Var:
 		// Nothing to do.
 
-	case *astext.Partial, *astext.PartialIndex:
+	case *astext.Partial:
 		// Noting to do.
 
+	case *astext.PartialIndex:
+		err = desugar(&node.Target, objLevel)
+		if err != nil {
+			return
+		}
+
 	default:
 		panic(fmt.Sprintf("Desugarer does not recognize ast: %s", reflect.TypeOf(node)))
 	}
@@ -0,0 +1,93 @@
+package token
+
+import (
+	"strings"
+
+	"github.com/google/go-jsonnet/ast"
+	"github.com/pkg/errors"
+)
+
+// DocComment returns the comment which documents the declaration at a
+// location. The comment must be immediately before the declaration, or
+// before the `local` keyword for local variables. Comments separated from
+// the declaration by a blank line are ignored.
+func DocComment(filename, source string, loc ast.Location) (string, error) {
+	tokens, err := Lex(filename, source)
+	if err != nil {
+		return "", errors.Wrap(err, "lexing source")
+	}
+
+	for i := range tokens {
+		if tokens[i].Loc.Begin == loc {
+			return tokenDoc(tokens, i), nil
+		}
+	}
+
+	return "", errors.Errorf("no token at %d:%d", loc.Line, loc.Column)
+}
+
+// tokenDoc returns the comment which documents the token at an index.
+func tokenDoc(tokens Tokens, i int) string {
+	if doc := fodderDoc(tokens[i].fodder, i == 0); doc != "" {
+		return doc
+	}
+
+	if i > 0 && tokens[i-1].Kind == TokenLocal {
+		return fodderDoc(tokens[i-1].fodder, i == 1)
+	}
+
+	return ""
+}
+
+// fodderDoc returns the comments at the end of fodder. Comments on the same
+// line as the previous token are not included unless the fodder is at the
+// start of the file.
+func fodderDoc(fodder Fodder, startOfFile bool) string {
+	var comments []string
+
+loop:
+	for i := len(fodder) - 1; i >= 0; i-- {
+		fe := fodder[i]
+
+		if fe.kind == fodderWhitespace {
+			if strings.Count(fe.data, "\n") > 1 {
+				break loop
+			}
+			continue
+		}
+
+		if !startOfFile && !fodderHasNewline(fodder[:i]) {
+			break loop
+		}
+
+		comments = append([]string{cleanComment(fe)}, comments...)
+	}
+
+	return strings.TrimSpace(strings.Join(comments, "\n"))
+}
+
+func fodderHasNewline(fodder Fodder) bool {
+	for _, fe := range fodder {
+		if strings.Contains(fe.data, "\n") {
+			return true
+		}
+	}
+
+	return false
+}
+
+// cleanComment removes leading space and `*` decoration from a comment.
+func cleanComment(fe FodderElement) string {
+	if fe.kind != fodderCommentC {
+		return strings.TrimSpace(fe.data)
+	}
+
+	var lines []string
+	for _, line := range strings.Split(fe.data, "\n") {
+		line = strings.TrimSpace(line)
+		line = strings.TrimSpace(strings.TrimLeft(line, "*"))
+		lines = append(lines, line)
+	}
+
+	return strings.TrimSpace(strings.Join(lines, "\n"))
+}
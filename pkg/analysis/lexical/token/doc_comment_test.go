@@ -0,0 +1,79 @@
+package token
+
+import (
+	"testing"
+
+	"github.com/google/go-jsonnet/ast"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestDocComment(t *testing.T) {
+	cases := []struct {
+		name     string
+		source   string
+		loc      ast.Location
+		expected string
+		isErr    bool
+	}{
+		{
+			name:     "field",
+			source:   "{\n  // the a field\n  a: 1,\n}",
+			loc:      ast.Location{Line: 3, Column: 3},
+			expected: "the a field",
+		},
+		{
+			name:     "local",
+			source:   "// a doc\nlocal a = 1;\na",
+			loc:      ast.Location{Line: 2, Column: 7},
+			expected: "a doc",
+		},
+		{
+			name:     "multiple line comments",
+			source:   "// line 1\n// line 2\nlocal a = 1;\na",
+			loc:      ast.Location{Line: 3, Column: 7},
+			expected: "line 1\nline 2",
+		},
+		{
+			name:     "block comment",
+			source:   "/**\n * line 1\n * line 2\n */\nlocal a = 1;\na",
+			loc:      ast.Location{Line: 5, Column: 7},
+			expected: "line 1\nline 2",
+		},
+		{
+			name:     "hash comment",
+			source:   "# doc\nlocal a = 1;\na",
+			loc:      ast.Location{Line: 2, Column: 7},
+			expected: "doc",
+		},
+		{
+			name:   "separated by blank line",
+			source: "// detached\n\nlocal a = 1;\na",
+			loc:    ast.Location{Line: 3, Column: 7},
+		},
+		{
+			name:   "trailing comment on previous line",
+			source: "{\n  a: 1, // about a\n  b: 2,\n}",
+			loc:    ast.Location{Line: 3, Column: 3},
+		},
+		{
+			name:   "no token at location",
+			source: "{}",
+			loc:    ast.Location{Line: 3, Column: 3},
+			isErr:  true,
+		},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			got, err := DocComment("file.jsonnet", tc.source, tc.loc)
+			if tc.isErr {
+				require.Error(t, err)
+				return
+			}
+
+			require.NoError(t, err)
+			assert.Equal(t, tc.expected, got)
+		})
+	}
+}
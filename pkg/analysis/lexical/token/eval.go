@@ -27,6 +27,10 @@ type evalScope struct {
 	store      map[ast.Identifier]ast.Node
 	references map[ast.Identifier][]reference
 	parents    map[ast.Node]ast.Node
+	// depth is the number of scopes enclosing this scope.
+	depth int
+	// depths are the depths of the scopes where identifiers were declared.
+	depths map[ast.Identifier]int
 }
 
 func newEvalScope(nc *NodeCache) (*evalScope, error) {
@@ -41,6 +45,7 @@ func newEvalScope(nc *NodeCache) (*evalScope, error) {
 		},
 		references: make(map[ast.Identifier][]reference),
 		parents:    make(map[ast.Node]ast.Node),
+		depths:     make(map[ast.Identifier]int),
 		nodeCache:  nc,
 	}, nil
 }
@@ -74,6 +79,8 @@ func (e *evalScope) set(id ast.Identifier, node ast.Node) error {
 		e.store[id] = node
 	}
 
+	e.depths[id] = e.depth
+
 	return nil
 }
 
@@ -145,12 +152,18 @@ func (e *evalScope) Clone() *evalScope {
 		references: e.references,
 		parents:    e.parents,
 		nodeCache:  e.nodeCache,
+		depth:      e.depth + 1,
+		depths:     make(map[ast.Identifier]int),
 	}
 
 	for k, v := range e.store {
 		clone.store[k] = v
 	}
 
+	for k, v := range e.depths {
+		clone.depths[k] = v
+	}
+
 	return clone
 }
 
@@ -219,10 +232,12 @@ func (e *evaluator) eval(parent, n ast.Node, parentScope *evalScope) {
 	case *ast.Import:
 	case *ast.ImportStr:
 	case *ast.Index:
-		path := resolveIndex(n)
-		if err := parentScope.refersTo(ast.Identifier(path[0]), n, path[1:]...); err != nil {
-			e.err = err
-			return
+		if isResolvableIndex(n) {
+			path := resolveIndex(n)
+			if err := parentScope.refersTo(ast.Identifier(path[0]), n, path[1:]...); err != nil {
+				e.err = err
+				return
+			}
 		}
 
 		e.eval(n, n.Target, parentScope)
@@ -248,8 +263,10 @@ func (e *evaluator) eval(parent, n ast.Node, parentScope *evalScope) {
 		}
 
 		e.eval(n, n.Body, s)
-	case *astext.Partial, *astext.PartialIndex:
+	case *astext.Partial:
 		// nothing to do
+	case *astext.PartialIndex:
+		e.eval(n, n.Target, parentScope)
 	case *ast.Self:
 	case *ast.SuperIndex:
 		e.eval(n, n.Index, parentScope)
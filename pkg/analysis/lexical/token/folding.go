@@ -0,0 +1,201 @@
+package token
+
+import (
+	"sort"
+	"strings"
+
+	"github.com/google/go-jsonnet/ast"
+)
+
+// FoldingKind is the kind of a folding range.
+type FoldingKind int
+
+const (
+	// FoldingCode folds an expression, e.g. an object or a function body.
+	FoldingCode FoldingKind = iota
+	// FoldingComment folds a multi-line comment or consecutive line
+	// comments.
+	FoldingComment
+	// FoldingImports folds consecutive imports.
+	FoldingImports
+)
+
+// FoldingRange is a range of lines which can be folded. Lines start at 1.
+type FoldingRange struct {
+	StartLine int
+	EndLine   int
+	Kind      FoldingKind
+}
+
+// FoldingRanges returns the folding ranges in source. Brackets, text blocks
+// and comments are found with the lexer, so they can be folded even if the
+// source doesn't parse. Function bodies are found in the AST.
+func FoldingRanges(filename, source string) ([]FoldingRange, error) {
+	tokens, err := Lex(filename, source)
+	if err != nil {
+		return nil, err
+	}
+
+	var ranges []FoldingRange
+	ranges = append(ranges, bracketFolds(tokens)...)
+	ranges = append(ranges, commentFolds(tokens)...)
+	ranges = append(ranges, importFolds(tokens)...)
+
+	for i := range tokens {
+		if tokens[i].Kind == TokenStringBlock {
+			ranges = appendFold(ranges, tokens[i].Loc.Begin.Line, tokens[i].Loc.End.Line, FoldingCode)
+		}
+	}
+
+	if node, err := ReadSource(filename, source, nil); err == nil {
+		ranges = append(ranges, functionFolds(node)...)
+	}
+
+	return uniqueFolds(ranges), nil
+}
+
+// appendFold appends a folding range if it spans multiple lines.
+func appendFold(ranges []FoldingRange, start, end int, kind FoldingKind) []FoldingRange {
+	if end <= start {
+		return ranges
+	}
+
+	return append(ranges, FoldingRange{StartLine: start, EndLine: end, Kind: kind})
+}
+
+// bracketFolds folds matching braces, brackets and parentheses. The line
+// with the closing bracket isn't folded, so it stays visible.
+func bracketFolds(tokens Tokens) []FoldingRange {
+	closing := map[TokenKind]TokenKind{
+		TokenBraceR:   TokenBraceL,
+		TokenBracketR: TokenBracketL,
+		TokenParenR:   TokenParenL,
+	}
+
+	var ranges []FoldingRange
+	var stack []*Token
+
+	for i := range tokens {
+		t := &tokens[i]
+		switch t.Kind {
+		case TokenBraceL, TokenBracketL, TokenParenL:
+			stack = append(stack, t)
+		case TokenBraceR, TokenBracketR, TokenParenR:
+			if len(stack) == 0 || stack[len(stack)-1].Kind != closing[t.Kind] {
+				continue
+			}
+
+			open := stack[len(stack)-1]
+			stack = stack[:len(stack)-1]
+
+			ranges = appendFold(ranges, open.Loc.Begin.Line, t.Loc.Begin.Line-1, FoldingCode)
+		}
+	}
+
+	return ranges
+}
+
+// commentFolds folds multi-line comments and runs of line comments. Fodder
+// doesn't have locations, so lines are counted from the end of the previous
+// token.
+func commentFolds(tokens Tokens) []FoldingRange {
+	var ranges []FoldingRange
+
+	line := 1
+	for i := range tokens {
+		runStart, runEnd := 0, 0
+
+		for _, fe := range tokens[i].fodder {
+			start := line
+			line += strings.Count(fe.data, "\n")
+
+			switch fe.kind {
+			case fodderWhitespace:
+				continue
+			case fodderCommentC:
+				ranges = appendFold(ranges, start, line, FoldingComment)
+				continue
+			}
+
+			if runEnd > 0 && start == runEnd+1 {
+				runEnd = start
+				continue
+			}
+
+			ranges = appendFold(ranges, runStart, runEnd, FoldingComment)
+			runStart, runEnd = start, start
+		}
+
+		ranges = appendFold(ranges, runStart, runEnd, FoldingComment)
+		line = tokens[i].Loc.End.Line
+	}
+
+	return ranges
+}
+
+// importFolds folds runs of `local x = import "..."` on consecutive lines.
+func importFolds(tokens Tokens) []FoldingRange {
+	var ranges []FoldingRange
+
+	runStart, runEnd := 0, 0
+	for i := 0; i+3 < len(tokens); i++ {
+		if tokens[i].Kind != TokenLocal ||
+			tokens[i+1].Kind != TokenIdentifier ||
+			tokens[i+2].Kind != TokenOperator || tokens[i+2].Data != "=" ||
+			(tokens[i+3].Kind != TokenImport && tokens[i+3].Kind != TokenImportStr) {
+			continue
+		}
+
+		line := tokens[i].Loc.Begin.Line
+		if runEnd > 0 && line == runEnd+1 {
+			runEnd = line
+			continue
+		}
+
+		ranges = appendFold(ranges, runStart, runEnd, FoldingImports)
+		runStart, runEnd = line, line
+	}
+
+	return appendFold(ranges, runStart, runEnd, FoldingImports)
+}
+
+// functionFolds folds function bodies from the line the function is
+// declared on.
+func functionFolds(node ast.Node) []FoldingRange {
+	var ranges []FoldingRange
+
+	sg := scanScope(node, nil)
+	for n := range sg.parents {
+		fn, ok := n.(*ast.Function)
+		if !ok || fn.Body == nil {
+			continue
+		}
+
+		ranges = appendFold(ranges, fn.Loc().Begin.Line, fn.Body.Loc().End.Line, FoldingCode)
+	}
+
+	return ranges
+}
+
+// uniqueFolds sorts folding ranges and keeps the largest range starting on
+// each line. Clients only support one range per line.
+func uniqueFolds(ranges []FoldingRange) []FoldingRange {
+	sort.Slice(ranges, func(i, j int) bool {
+		if ranges[i].StartLine != ranges[j].StartLine {
+			return ranges[i].StartLine < ranges[j].StartLine
+		}
+
+		return ranges[i].EndLine > ranges[j].EndLine
+	})
+
+	var out []FoldingRange
+	for _, r := range ranges {
+		if len(out) > 0 && out[len(out)-1].StartLine == r.StartLine {
+			continue
+		}
+
+		out = append(out, r)
+	}
+
+	return out
+}
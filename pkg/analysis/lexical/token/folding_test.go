@@ -0,0 +1,80 @@
+package token
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestFoldingRanges(t *testing.T) {
+	cases := []struct {
+		name     string
+		source   string
+		expected []FoldingRange
+	}{
+		{
+			name:   "object and array",
+			source: "{\n  a: 1,\n  b: [\n    1,\n  ],\n}",
+			expected: []FoldingRange{
+				{StartLine: 1, EndLine: 5, Kind: FoldingCode},
+				{StartLine: 3, EndLine: 4, Kind: FoldingCode},
+			},
+		},
+		{
+			name:   "function body",
+			source: "local f = function(x)\n  x +\n  1;\nf(1)",
+			expected: []FoldingRange{
+				{StartLine: 1, EndLine: 3, Kind: FoldingCode},
+			},
+		},
+		{
+			name:   "text block",
+			source: "{\n  a: |||\n    text\n  |||,\n}",
+			expected: []FoldingRange{
+				{StartLine: 1, EndLine: 4, Kind: FoldingCode},
+				{StartLine: 2, EndLine: 4, Kind: FoldingCode},
+			},
+		},
+		{
+			name:   "comments",
+			source: "/*\n a\n*/\n// b\n// c\n# d\n\n// e\nx",
+			expected: []FoldingRange{
+				{StartLine: 1, EndLine: 3, Kind: FoldingComment},
+				{StartLine: 4, EndLine: 6, Kind: FoldingComment},
+			},
+		},
+		{
+			name: "imports",
+			source: "local a = import 'a.libsonnet';\n" +
+				"local b = import 'b.libsonnet';\n" +
+				"local c = importstr 'c.txt';\n" +
+				"\n" +
+				"local d = import 'd.libsonnet';\n" +
+				"a",
+			expected: []FoldingRange{
+				{StartLine: 1, EndLine: 3, Kind: FoldingImports},
+			},
+		},
+		{
+			name:   "does not parse",
+			source: "{\n  a: [\n    1,\n  ]\n",
+			expected: []FoldingRange{
+				{StartLine: 2, EndLine: 3, Kind: FoldingCode},
+			},
+		},
+		{
+			name:   "single line",
+			source: "{a: [1, 2]}",
+		},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			got, err := FoldingRanges("file.jsonnet", tc.source)
+			require.NoError(t, err)
+
+			assert.Equal(t, tc.expected, got)
+		})
+	}
+}
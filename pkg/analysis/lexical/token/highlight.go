@@ -2,75 +2,58 @@ package token
 
 import (
 	"context"
-	"fmt"
 
 	jpos "github.com/tminor/jsonnet-language-server/pkg/util/position"
-	"github.com/google/go-jsonnet/ast"
 	opentracing "github.com/opentracing/opentracing-go"
 )
 
-// Highlight returns locations to highlight given source and a position.
-func Highlight(ctx context.Context, filepath, source string, pos jpos.Position, nodeCache *NodeCache) (*jpos.Locations, error) {
+// HighlightKind is the kind of a highlight.
+type HighlightKind int
+
+const (
+	// HighlightRead is an identifier which refers to a declaration.
+	HighlightRead HighlightKind = iota
+	// HighlightWrite is where an identifier is declared.
+	HighlightWrite
+)
+
+// DocumentHighlight is a location to highlight.
+type DocumentHighlight struct {
+	Location jpos.Location
+	Kind     HighlightKind
+}
+
+// Highlight returns locations to highlight given source and a position. The
+// position can be a declaration or a reference to a declaration. The
+// declarations in source are highlighted as writes and the references are
+// highlighted as reads.
+func Highlight(ctx context.Context, filepath, source string, pos jpos.Position, nodeCache *NodeCache) ([]DocumentHighlight, error) {
 	span, _ := opentracing.StartSpanFromContext(ctx, "highlight")
 	defer span.Finish()
 
-	node, err := ReadSource(filepath, source, nil)
+	ri, err := readReferenceIndex(filepath, source, nodeCache, nil)
 	if err != nil {
 		return nil, err
 	}
 
-	sg := scanScope(node, nodeCache)
-
-	_, s, err := sg.at(pos)
-	if err != nil {
-		return nil, err
+	decl, ok := ri.at(pos)
+	if !ok {
+		return nil, nil
 	}
 
-	locations := s.refAt(pos)
-	return &locations, nil
+	var highlights []DocumentHighlight
 
-	// spew.Dump(pos, s.refMap)
-
-	// id, path := idNode(found, pos, s)
-
-	// return s.refersTo(id, path...), nil
-}
-
-func idNode(node ast.Node, pos jpos.Position, s *scope) (ast.Identifier, []string) {
-	var id ast.Identifier
-	var path []string
-	switch found := node.(type) {
-	case *ast.DesugaredObject:
-		return idNode(s.parent(found), pos, s)
-	case *ast.Function:
-		for paramID, loc := range found.Parameters.RequiredLocs {
-			if pos.IsInJsonnetRange(loc) {
-				id = paramID
-				break
-			}
+	for _, loc := range ri.declarationsOf(decl) {
+		if loc.URI() != filepath {
+			continue
 		}
 
-		for _, param := range found.Parameters.Optional {
-			if pos.IsInJsonnetRange(param.Loc) {
-				id = param.Name
-				break
-			}
-		}
-	case *ast.Index:
-		indexPath := resolveIndex(found)
-		id = ast.Identifier(indexPath[0])
-		path = indexPath[1:]
-	case *ast.Local:
-		for _, bind := range found.Binds {
-			if pos.IsInJsonnetRange(bind.VarLoc) {
-				id = bind.Variable
-			}
-		}
-	case *ast.Var:
-		id = found.Id
-	default:
-		panic(fmt.Sprintf("unable to find nodes of type %T", found))
+		highlights = append(highlights, DocumentHighlight{Location: loc, Kind: HighlightWrite})
+	}
+
+	for _, loc := range ri.referencesTo(decl) {
+		highlights = append(highlights, DocumentHighlight{Location: loc, Kind: HighlightRead})
 	}
 
-	return id, path
+	return highlights, nil
 }
@@ -16,61 +16,69 @@ func TestHighlight(t *testing.T) {
 		name      string
 		source    string
 		positions []jpos.Position
-		locs      []jpos.Location
+		expected  []DocumentHighlight
 		isErr     bool
 	}{
 		{
 			name:      "bind var",
 			source:    "local x=1; x",
 			positions: []jpos.Position{jpos.New(1, 7), jpos.New(1, 12)},
-			locs: []jpos.Location{
-				jpos.NewLocation(file, jpos.NewRangeFromCoords(1, 7, 1, 8)),
-				jpos.NewLocation(file, jpos.NewRangeFromCoords(1, 12, 1, 13)),
+			expected: []DocumentHighlight{
+				{Location: jpos.NewLocation(file, jpos.NewRangeFromCoords(1, 7, 1, 8)), Kind: HighlightWrite},
+				{Location: jpos.NewLocation(file, jpos.NewRangeFromCoords(1, 12, 1, 13)), Kind: HighlightRead},
+			},
+		},
+		{
+			name:      "declaration without references",
+			source:    "local x=1; 2",
+			positions: []jpos.Position{jpos.New(1, 7)},
+			expected: []DocumentHighlight{
+				{Location: jpos.NewLocation(file, jpos.NewRangeFromCoords(1, 7, 1, 8)), Kind: HighlightWrite},
 			},
 		},
 		{
 			name:      "target parameter in bind function",
 			source:    "local id(x)=x; id(1)",
 			positions: []jpos.Position{jpos.New(1, 10), jpos.New(1, 13)},
-			locs: []jpos.Location{
-				jpos.NewLocation(file, jpos.NewRangeFromCoords(1, 10, 1, 11)),
-				jpos.NewLocation(file, jpos.NewRangeFromCoords(1, 13, 1, 14)),
+			expected: []DocumentHighlight{
+				{Location: jpos.NewLocation(file, jpos.NewRangeFromCoords(1, 10, 1, 11)), Kind: HighlightWrite},
+				{Location: jpos.NewLocation(file, jpos.NewRangeFromCoords(1, 13, 1, 14)), Kind: HighlightRead},
+			},
+		},
+		{
+			name:      "apply",
+			source:    "local o={id(x)::x}; o.id(1)",
+			positions: []jpos.Position{jpos.New(1, 10), jpos.New(1, 23)},
+			expected: []DocumentHighlight{
+				{Location: jpos.NewLocation(file, jpos.NewRangeFromCoords(1, 10, 1, 12)), Kind: HighlightWrite},
+				{Location: jpos.NewLocation(file, jpos.NewRangeFromCoords(1, 23, 1, 25)), Kind: HighlightRead},
 			},
 		},
-		// {
-		// 	name:      "apply",
-		// 	source:    "local o={id(x)::x}; o.id(1)",
-		// 	positions: []jpos.Position{jpos.New(1, 10), jpos.New(1, 23)},
-		// 	locs: []jpos.Location{
-		// 		jpos.NewLocation(file, jpos.NewRangeFromCoords(1, 10, 1, 12)),
-		// 		jpos.NewLocation(file, jpos.NewRangeFromCoords(1, 23, 1, 25)),
-		// 	},
-		// },
 		{
 			name:      "shadow: function parameter",
 			source:    "local x=1; local id(x)=x; id(1)",
 			positions: []jpos.Position{jpos.New(1, 21), jpos.New(1, 24)},
-			locs: []jpos.Location{
-				jpos.NewLocation(file, jpos.NewRangeFromCoords(1, 21, 1, 22)),
-				jpos.NewLocation(file, jpos.NewRangeFromCoords(1, 24, 1, 25)),
+			expected: []DocumentHighlight{
+				{Location: jpos.NewLocation(file, jpos.NewRangeFromCoords(1, 21, 1, 22)), Kind: HighlightWrite},
+				{Location: jpos.NewLocation(file, jpos.NewRangeFromCoords(1, 24, 1, 25)), Kind: HighlightRead},
 			},
 		},
 		{
 			name:      "target in array index",
 			source:    "local x=1, i=1; local a=[x]; a[i]",
 			positions: []jpos.Position{jpos.New(1, 32)},
-			locs: []jpos.Location{
-				jpos.NewLocation(file, jpos.NewRangeFromCoords(1, 12, 1, 13)),
-				jpos.NewLocation(file, jpos.NewRangeFromCoords(1, 32, 1, 33)),
+			expected: []DocumentHighlight{
+				{Location: jpos.NewLocation(file, jpos.NewRangeFromCoords(1, 12, 1, 13)), Kind: HighlightWrite},
+				{Location: jpos.NewLocation(file, jpos.NewRangeFromCoords(1, 32, 1, 33)), Kind: HighlightRead},
 			},
 		},
 		{
 			name:      "target index in body",
 			source:    "local o={a:{b:{c:{d:'e'}}}}; o.a.b.c.d",
 			positions: []jpos.Position{jpos.New(1, 38), jpos.New(1, 19)},
-			locs: []jpos.Location{
-				jpos.NewLocation(file, jpos.NewRangeFromCoords(1, 19, 1, 20)),
-				jpos.NewLocation(file, jpos.NewRangeFromCoords(1, 38, 1, 39)),
+			expected: []DocumentHighlight{
+				{Location: jpos.NewLocation(file, jpos.NewRangeFromCoords(1, 19, 1, 20)), Kind: HighlightWrite},
+				{Location: jpos.NewLocation(file, jpos.NewRangeFromCoords(1, 38, 1, 39)), Kind: HighlightRead},
 			},
 		},
 
@@ -78,47 +86,85 @@ func TestHighlight(t *testing.T) {
 			name:      "self",
 			source:    `{n: 1, m: self.n + 1}`,
 			positions: []jpos.Position{jpos.New(1, 16)},
-			locs: []jpos.Location{
-				jpos.NewLocation(file, jpos.NewRangeFromCoords(1, 16, 1, 17)),
-				jpos.NewLocation(file, jpos.NewRangeFromCoords(1, 2, 1, 3)),
+			expected: []DocumentHighlight{
+				{Location: jpos.NewLocation(file, jpos.NewRangeFromCoords(1, 2, 1, 3)), Kind: HighlightWrite},
+				{Location: jpos.NewLocation(file, jpos.NewRangeFromCoords(1, 16, 1, 17)), Kind: HighlightRead},
 			},
 		},
 		{
 			name:      "self nested",
 			source:    `{person1: {name: "Alice", welcome: "Hello " + self.name + "!"}, person2: self.person1 {name: "Bob"}}`,
 			positions: []jpos.Position{jpos.New(1, 52)},
-			locs: []jpos.Location{
-				jpos.NewLocation(file, jpos.NewRangeFromCoords(1, 12, 1, 16)),
-				jpos.NewLocation(file, jpos.NewRangeFromCoords(1, 52, 1, 56)),
+			expected: []DocumentHighlight{
+				{Location: jpos.NewLocation(file, jpos.NewRangeFromCoords(1, 12, 1, 16)), Kind: HighlightWrite},
+				{Location: jpos.NewLocation(file, jpos.NewRangeFromCoords(1, 88, 1, 92)), Kind: HighlightWrite},
+				{Location: jpos.NewLocation(file, jpos.NewRangeFromCoords(1, 52, 1, 56)), Kind: HighlightRead},
+			},
+		},
+		{
+			name:      "dollar",
+			source:    `{a: 1, b: {c: $.a}}`,
+			positions: []jpos.Position{jpos.New(1, 2), jpos.New(1, 17)},
+			expected: []DocumentHighlight{
+				{Location: jpos.NewLocation(file, jpos.NewRangeFromCoords(1, 2, 1, 3)), Kind: HighlightWrite},
+				{Location: jpos.NewLocation(file, jpos.NewRangeFromCoords(1, 17, 1, 18)), Kind: HighlightRead},
+			},
+		},
+		{
+			name:      "super",
+			source:    `{a: 1} + {a: super.a + 1}`,
+			positions: []jpos.Position{jpos.New(1, 2), jpos.New(1, 11), jpos.New(1, 20)},
+			expected: []DocumentHighlight{
+				{Location: jpos.NewLocation(file, jpos.NewRangeFromCoords(1, 2, 1, 3)), Kind: HighlightWrite},
+				{Location: jpos.NewLocation(file, jpos.NewRangeFromCoords(1, 11, 1, 12)), Kind: HighlightWrite},
+				{Location: jpos.NewLocation(file, jpos.NewRangeFromCoords(1, 20, 1, 21)), Kind: HighlightRead},
+			},
+		},
+		{
+			name:      "super bracket index",
+			source:    `{a: 1} + {a: super['a'] + 1}`,
+			positions: []jpos.Position{jpos.New(1, 2), jpos.New(1, 21)},
+			expected: []DocumentHighlight{
+				{Location: jpos.NewLocation(file, jpos.NewRangeFromCoords(1, 2, 1, 3)), Kind: HighlightWrite},
+				{Location: jpos.NewLocation(file, jpos.NewRangeFromCoords(1, 11, 1, 12)), Kind: HighlightWrite},
+				{Location: jpos.NewLocation(file, jpos.NewRangeFromCoords(1, 21, 1, 22)), Kind: HighlightRead},
+			},
+		},
+		{
+			name:      "field through local",
+			source:    `local o = {a: 1}; o.a`,
+			positions: []jpos.Position{jpos.New(1, 12), jpos.New(1, 21)},
+			expected: []DocumentHighlight{
+				{Location: jpos.NewLocation(file, jpos.NewRangeFromCoords(1, 12, 1, 13)), Kind: HighlightWrite},
+				{Location: jpos.NewLocation(file, jpos.NewRangeFromCoords(1, 21, 1, 22)), Kind: HighlightRead},
+			},
+		},
+		{
+			name:      "bracket index",
+			source:    `local o = {'kube-system': 1}; o['kube-system'] + o["kube-system"]`,
+			positions: []jpos.Position{jpos.New(1, 13), jpos.New(1, 35), jpos.New(1, 53)},
+			expected: []DocumentHighlight{
+				{Location: jpos.NewLocation(file, jpos.NewRangeFromCoords(1, 12, 1, 25)), Kind: HighlightWrite},
+				{Location: jpos.NewLocation(file, jpos.NewRangeFromCoords(1, 34, 1, 45)), Kind: HighlightRead},
+				{Location: jpos.NewLocation(file, jpos.NewRangeFromCoords(1, 53, 1, 64)), Kind: HighlightRead},
 			},
 		},
 	}
 
 	for _, tc := range cases {
-		if tc.name != "target index in body" {
-			continue
-		}
 		t.Run(tc.name, func(t *testing.T) {
 			for _, pos := range tc.positions {
 				nc := NewNodeCache()
 				ctx := context.Background()
-				locations, err := Highlight(ctx, file, tc.source, pos, nc)
+				highlights, err := Highlight(ctx, file, tc.source, pos, nc)
 				if tc.isErr {
 					require.Error(t, err)
 					return
 				}
 				require.NoError(t, err)
 
-				expected := jpos.Locations{}
-				for _, l := range tc.locs {
-					expected.Add(l)
-				}
-
-				assert.True(t, expected.Equal(locations), "\n\texpected %s\n\tgot: %s",
-					expected.String(), locations.String())
+				assert.Equal(t, tc.expected, highlights, "position %s", pos.String())
 			}
-
 		})
 	}
-
 }
@@ -137,7 +137,7 @@ func (i *identifier) identify(n ast.Node) (Identity, error) {
 		return i.variable(n)
 	case *ast.Function, *ast.Object:
 		return NewItem(n), nil
-	case nil, *astext.Partial:
+	case nil, *astext.Partial, *ast.Self, *ast.SuperIndex:
 		return IdentifyNoMatch, nil
 	case *ast.Array, *ast.DesugaredObject, *ast.Import,
 		*ast.LiteralBoolean, *ast.LiteralNumber, *ast.LiteralString:
@@ -0,0 +1,67 @@
+package token
+
+import (
+	"context"
+
+	jpos "github.com/tminor/jsonnet-language-server/pkg/util/position"
+	opentracing "github.com/opentracing/opentracing-go"
+)
+
+// ImplementationSet is the declarations of a field in a composed object
+// which are related to the field at a position.
+type ImplementationSet struct {
+	// Declaration is the declaration of the field at the position.
+	Declaration jpos.Location
+	// Bases are the declarations the field overrides, or merges with when it
+	// is declared with `+:`.
+	Bases []jpos.Location
+	// Overrides are the declarations which override or merge with the
+	// field.
+	Overrides []jpos.Location
+}
+
+// Implementations returns the declarations a field declared or referred to
+// at a position overrides and the declarations in source which override it.
+func Implementations(ctx context.Context, filepath, source string, pos jpos.Position, nodeCache *NodeCache, libPaths []string) (ImplementationSet, error) {
+	span, _ := opentracing.StartSpanFromContext(ctx, "implementations")
+	defer span.Finish()
+
+	ri, err := readReferenceIndex(filepath, source, nodeCache, libPaths)
+	if err != nil {
+		return ImplementationSet{}, err
+	}
+
+	decl, ok := ri.at(pos)
+	if !ok || ri.locals[decl] {
+		return ImplementationSet{}, nil
+	}
+
+	is := ImplementationSet{
+		Declaration: decl,
+		Bases:       ri.basesOf(decl),
+		Overrides:   ri.overridesOf(decl),
+	}
+
+	return is, nil
+}
+
+// FileOverrides returns the declarations in source which override a field
+// declared in another file.
+func FileOverrides(ctx context.Context, filepath, source string, decl jpos.Location, nodeCache *NodeCache, libPaths []string) ([]jpos.Location, error) {
+	span, _ := opentracing.StartSpanFromContext(ctx, "fileOverrides")
+	defer span.Finish()
+
+	ri, err := readReferenceIndex(filepath, source, nodeCache, libPaths)
+	if err != nil {
+		return nil, err
+	}
+
+	var locations []jpos.Location
+	for _, loc := range ri.overridesOf(decl) {
+		if loc.URI() == filepath {
+			locations = append(locations, loc)
+		}
+	}
+
+	return locations, nil
+}
@@ -0,0 +1,91 @@
+package token
+
+import (
+	"context"
+	"testing"
+
+	jpos "github.com/tminor/jsonnet-language-server/pkg/util/position"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const implementationSource = "local base = import 'references.libsonnet';\n" +
+	"local env = base { name: 'env' };\n" +
+	"env {\n" +
+	"  name: 'app',\n" +
+	"  labels+: { app: 'app' },\n" +
+	"}"
+
+func TestImplementations(t *testing.T) {
+	file := "testdata/file.jsonnet"
+	lib := "testdata/references.libsonnet"
+
+	cases := []struct {
+		name     string
+		pos      jpos.Position
+		expected ImplementationSet
+	}{
+		{
+			name: "override",
+			pos:  jpos.New(4, 4),
+			expected: ImplementationSet{
+				Declaration: jpos.NewLocation(file, jpos.NewRangeFromCoords(4, 3, 4, 7)),
+				Bases: []jpos.Location{
+					jpos.NewLocation(file, jpos.NewRangeFromCoords(2, 20, 2, 24)),
+					jpos.NewLocation(lib, jpos.NewRangeFromCoords(2, 3, 2, 7)),
+				},
+			},
+		},
+		{
+			name: "overridden",
+			pos:  jpos.New(2, 21),
+			expected: ImplementationSet{
+				Declaration: jpos.NewLocation(file, jpos.NewRangeFromCoords(2, 20, 2, 24)),
+				Bases: []jpos.Location{
+					jpos.NewLocation(lib, jpos.NewRangeFromCoords(2, 3, 2, 7)),
+				},
+				Overrides: []jpos.Location{
+					jpos.NewLocation(file, jpos.NewRangeFromCoords(4, 3, 4, 7)),
+				},
+			},
+		},
+		{
+			name: "merged field",
+			pos:  jpos.New(5, 15),
+			expected: ImplementationSet{
+				Declaration: jpos.NewLocation(file, jpos.NewRangeFromCoords(5, 14, 5, 17)),
+				Bases: []jpos.Location{
+					jpos.NewLocation(lib, jpos.NewRangeFromCoords(4, 5, 4, 8)),
+				},
+			},
+		},
+		{
+			name: "local",
+			pos:  jpos.New(1, 8),
+		},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			got, err := Implementations(context.Background(), file, implementationSource, tc.pos, NewNodeCache(), nil)
+			require.NoError(t, err)
+
+			assert.Equal(t, tc.expected, got)
+		})
+	}
+}
+
+func TestFileOverrides(t *testing.T) {
+	file := "testdata/file.jsonnet"
+	decl := jpos.NewLocation("testdata/references.libsonnet", jpos.NewRangeFromCoords(2, 3, 2, 7))
+
+	expected := []jpos.Location{
+		jpos.NewLocation(file, jpos.NewRangeFromCoords(2, 20, 2, 24)),
+		jpos.NewLocation(file, jpos.NewRangeFromCoords(4, 3, 4, 7)),
+	}
+
+	got, err := FileOverrides(context.Background(), file, implementationSource, decl, NewNodeCache(), nil)
+	require.NoError(t, err)
+
+	assert.Equal(t, expected, got)
+}
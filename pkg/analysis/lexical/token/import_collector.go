@@ -77,16 +77,57 @@ func (ic *ImportCollector) Collect(filename string, shallow bool) ([]string, err
 	return imports, nil
 }
 
-// ImportPath finds the absolute path to an import.
+// ImportPath finds the absolute path to an import in the lib paths.
 func ImportPath(filename string, libPaths []string) (string, error) {
-	for _, libPath := range libPaths {
-		path := filepath.Join(libPath, filename)
-		if _, err := os.Stat(path); os.IsNotExist(err) {
+	return resolveImport("", filename, libPaths)
+}
+
+// resolveImport finds the path for an import. Like Jsonnet, it looks in the
+// importing file's directory before the lib paths.
+func resolveImport(importer, name string, libPaths []string) (string, error) {
+	matches := searchImport(importer, name, libPaths, false)
+	if len(matches) == 0 {
+		return "", errors.Errorf("import %q not found", name)
+	}
+
+	return matches[0].path, nil
+}
+
+// importMatch is a file an import can resolve to.
+type importMatch struct {
+	path string
+	// dir is the directory the file was found in.
+	dir string
+}
+
+// searchImport finds the files an import can resolve to in the order
+// Jsonnet looks for them. An absolute import is only looked up as written.
+// Other imports are looked up in the importing file's directory and then
+// the lib paths. The first match is the file which is imported. If all is
+// false, the search stops at the first match.
+func searchImport(importer, name string, libPaths []string, all bool) []importMatch {
+	var dirs []string
+	switch {
+	case filepath.IsAbs(name):
+		dirs = []string{""}
+	case importer != "":
+		dirs = append([]string{filepath.Dir(importer)}, libPaths...)
+	default:
+		dirs = libPaths
+	}
+
+	var matches []importMatch
+	for _, dir := range dirs {
+		path := filepath.Join(dir, name)
+		if _, err := os.Stat(path); err != nil {
 			continue
 		}
 
-		return path, nil
+		matches = append(matches, importMatch{path: path, dir: dir})
+		if !all {
+			break
+		}
 	}
 
-	return "", errors.Errorf("import %q not found in lib path", filename)
+	return matches
 }
@@ -0,0 +1,101 @@
+package token
+
+import (
+	"io/ioutil"
+	"strings"
+
+	"github.com/google/go-jsonnet/ast"
+	"github.com/pkg/errors"
+)
+
+// ImportDescription describes how an import was resolved.
+type ImportDescription struct {
+	// Name is the imported name as written in source.
+	Name string
+	// Path is the absolute path the import resolved to.
+	Path string
+	// LibPath is the directory the import was found in. It is either the
+	// importing file's directory or a lib path.
+	LibPath string
+	// Shadowed are other lib paths which contain the import, but are not
+	// used because LibPath is searched first.
+	Shadowed []string
+	// Fields are the top level fields of the imported file. They are empty
+	// for importstr.
+	Fields []Field
+	// Documentation is the imported file's header comment.
+	Documentation string
+}
+
+// DescribeImport describes how an import in a file is resolved. Imports are
+// resolved relative to the importing file first and then the lib paths.
+func DescribeImport(importer, name string, isImportStr bool, libPaths []string, nc *NodeCache) (*ImportDescription, error) {
+	id := &ImportDescription{
+		Name: name,
+	}
+
+	for _, match := range searchImport(importer, name, libPaths, true) {
+		if id.Path == "" {
+			id.Path = match.path
+			id.LibPath = match.dir
+			continue
+		}
+
+		if match.path != id.Path {
+			id.Shadowed = append(id.Shadowed, match.dir)
+		}
+	}
+
+	if id.Path == "" {
+		return nil, errors.Errorf("import %q not found", name)
+	}
+
+	/* #nosec */
+	source, err := ioutil.ReadFile(id.Path)
+	if err != nil {
+		return nil, err
+	}
+
+	if doc, err := FileComment(id.Path, string(source)); err == nil {
+		id.Documentation = doc
+	}
+
+	if isImportStr {
+		return id, nil
+	}
+
+	or := newObjectResolver(importer, nil, libPaths, nc)
+	layers, err := or.layers(nil, &ast.Import{File: makeStr(name)})
+	if err == nil {
+		id.Fields = or.fields(layers)
+	}
+
+	return id, nil
+}
+
+// FileComment returns the comment at the start of a file. The comment ends
+// at the first blank line or token.
+func FileComment(filename, source string) (string, error) {
+	tokens, err := Lex(filename, source)
+	if err != nil {
+		return "", errors.Wrap(err, "lexing source")
+	}
+
+	if len(tokens) == 0 {
+		return "", nil
+	}
+
+	var comments []string
+	for _, fe := range tokens[0].fodder {
+		if fe.kind == fodderWhitespace {
+			if len(comments) > 0 && strings.Count(fe.data, "\n") > 1 {
+				break
+			}
+			continue
+		}
+
+		comments = append(comments, cleanComment(fe))
+	}
+
+	return strings.TrimSpace(strings.Join(comments, "\n")), nil
+}
@@ -0,0 +1,117 @@
+package token
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestDescribeImport(t *testing.T) {
+	dir, err := ioutil.TempDir("", "")
+	require.NoError(t, err)
+	defer os.RemoveAll(dir)
+
+	files := map[string]string{
+		"app/local.libsonnet": "{ a: 1 }",
+		"lib1/lib.libsonnet":  "// lib header\n// second line\n\nlocal x = 1;\n{ a: x, b():: 2 }",
+		"lib2/lib.libsonnet":  "{ c: 3 }",
+		"lib2/text.txt":       "text",
+	}
+
+	for name, content := range files {
+		path := filepath.Join(dir, name)
+		require.NoError(t, os.MkdirAll(filepath.Dir(path), 0755))
+		require.NoError(t, ioutil.WriteFile(path, []byte(content), 0600))
+	}
+
+	importer := filepath.Join(dir, "app", "main.jsonnet")
+	libPaths := []string{filepath.Join(dir, "lib1"), filepath.Join(dir, "lib2")}
+
+	cases := []struct {
+		name        string
+		importName  string
+		isImportStr bool
+		path        string
+		libPath     string
+		shadowed    []string
+		fields      []string
+		doc         string
+		isErr       bool
+	}{
+		{
+			name:       "relative to importer",
+			importName: "local.libsonnet",
+			path:       filepath.Join(dir, "app", "local.libsonnet"),
+			libPath:    filepath.Join(dir, "app"),
+			fields:     []string{"a"},
+		},
+		{
+			name:       "shadows lib path",
+			importName: "lib.libsonnet",
+			path:       filepath.Join(dir, "lib1", "lib.libsonnet"),
+			libPath:    filepath.Join(dir, "lib1"),
+			shadowed:   []string{filepath.Join(dir, "lib2")},
+			fields:     []string{"a", "b"},
+			doc:        "lib header\nsecond line",
+		},
+		{
+			name:        "importstr",
+			importName:  "text.txt",
+			isImportStr: true,
+			path:        filepath.Join(dir, "lib2", "text.txt"),
+			libPath:     filepath.Join(dir, "lib2"),
+		},
+		{
+			name:       "not found",
+			importName: "missing.libsonnet",
+			isErr:      true,
+		},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			id, err := DescribeImport(importer, tc.importName, tc.isImportStr, libPaths, NewNodeCache())
+			if tc.isErr {
+				require.Error(t, err)
+				return
+			}
+			require.NoError(t, err)
+
+			assert.Equal(t, tc.path, id.Path)
+			assert.Equal(t, tc.libPath, id.LibPath)
+			assert.Equal(t, tc.shadowed, id.Shadowed)
+			assert.Equal(t, tc.doc, id.Documentation)
+
+			var fields []string
+			for _, field := range id.Fields {
+				fields = append(fields, field.Name)
+			}
+			assert.Equal(t, tc.fields, fields)
+		})
+	}
+}
+
+func TestFileComment(t *testing.T) {
+	cases := []struct {
+		name     string
+		source   string
+		expected string
+	}{
+		{name: "header", source: "// header\n\n{}", expected: "header"},
+		{name: "block header", source: "/*\n * header\n */\n{}", expected: "header"},
+		{name: "no header", source: "{}", expected: ""},
+		{name: "only first comment block", source: "// one\n\n// two\n{}", expected: "one"},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			got, err := FileComment("file.jsonnet", tc.source)
+			require.NoError(t, err)
+			assert.Equal(t, tc.expected, got)
+		})
+	}
+}
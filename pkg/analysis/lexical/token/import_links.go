@@ -0,0 +1,97 @@
+package token
+
+import jpos "github.com/tminor/jsonnet-language-server/pkg/util/position"
+
+const (
+	// keywordImportBin is the keyword for importing a file as bytes. It is
+	// lexed as an identifier.
+	keywordImportBin = "importbin"
+)
+
+// ImportLink is an import in a file and the file it resolves to.
+type ImportLink struct {
+	// Name is the imported name as written in source.
+	Name string
+	// Range is the range of the imported name.
+	Range jpos.Range
+	// Path is the absolute path the import resolved to. It is blank if the
+	// import couldn't be resolved.
+	Path string
+	// LibPath is the directory the import was found in. It is either the
+	// importing file's directory or a lib path.
+	LibPath string
+}
+
+// IsResolved returns true if the import resolved to a file.
+func (il *ImportLink) IsResolved() bool {
+	return il.Path != ""
+}
+
+// ImportLinks returns the imports in a file. Imports are found with the
+// lexer, so they are found even if the source doesn't parse. Imports are
+// resolved relative to the importing file first and then the lib paths.
+func ImportLinks(filename, source string, libPaths []string) ([]ImportLink, error) {
+	tokens, err := Lex(filename, source)
+	if err != nil {
+		return nil, err
+	}
+
+	var links []ImportLink
+	for i := 0; i+1 < len(tokens); i++ {
+		if !isImportToken(&tokens[i]) {
+			continue
+		}
+
+		name := &tokens[i+1]
+		if name.Kind < TokenStringBlock || name.Kind > TokenVerbatimStringSingle {
+			continue
+		}
+
+		link := ImportLink{
+			Name:  name.Data,
+			Range: importNameRange(name),
+		}
+
+		if matches := searchImport(filename, name.Data, libPaths, false); len(matches) > 0 {
+			link.Path = matches[0].path
+			link.LibPath = matches[0].dir
+		}
+
+		links = append(links, link)
+	}
+
+	return links, nil
+}
+
+func isImportToken(t *Token) bool {
+	switch t.Kind {
+	case TokenImport, TokenImportStr:
+		return true
+	case TokenIdentifier:
+		return t.Data == keywordImportBin
+	default:
+		return false
+	}
+}
+
+// importNameRange is the range of an imported name. Quotes are not included
+// for strings on a single line.
+func importNameRange(t *Token) jpos.Range {
+	r := jpos.FromJsonnetRange(t.Loc)
+
+	switch t.Kind {
+	case TokenStringDouble, TokenStringSingle:
+	case TokenVerbatimStringDouble, TokenVerbatimStringSingle:
+		r.Start = jpos.New(r.Start.Line(), r.Start.Column()+1)
+	default:
+		return r
+	}
+
+	if r.Start.Line() != r.End.Line() {
+		return r
+	}
+
+	return jpos.NewRange(
+		jpos.New(r.Start.Line(), r.Start.Column()+1),
+		jpos.New(r.End.Line(), r.End.Column()-1))
+}
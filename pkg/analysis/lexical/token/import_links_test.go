@@ -0,0 +1,69 @@
+package token
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+
+	jpos "github.com/tminor/jsonnet-language-server/pkg/util/position"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestImportLinks(t *testing.T) {
+	dir, err := ioutil.TempDir("", "")
+	require.NoError(t, err)
+	defer os.RemoveAll(dir)
+
+	files := map[string]string{
+		"app/local.libsonnet": "{}",
+		"lib/lib.libsonnet":   "{}",
+		"lib/data.bin":        "data",
+	}
+
+	for name, content := range files {
+		path := filepath.Join(dir, name)
+		require.NoError(t, os.MkdirAll(filepath.Dir(path), 0755))
+		require.NoError(t, ioutil.WriteFile(path, []byte(content), 0600))
+	}
+
+	filename := filepath.Join(dir, "app", "main.jsonnet")
+	libPath := filepath.Join(dir, "lib")
+
+	source := "local a = import 'local.libsonnet';\n" +
+		"local b = importstr \"lib.libsonnet\";\n" +
+		"local c = importbin 'data.bin';\n" +
+		"local d = import 'missing.libsonnet';\n" +
+		"{}"
+
+	got, err := ImportLinks(filename, source, []string{libPath})
+	require.NoError(t, err)
+
+	expected := []ImportLink{
+		{
+			Name:    "local.libsonnet",
+			Range:   jpos.NewRangeFromCoords(1, 19, 1, 34),
+			Path:    filepath.Join(dir, "app", "local.libsonnet"),
+			LibPath: filepath.Join(dir, "app"),
+		},
+		{
+			Name:    "lib.libsonnet",
+			Range:   jpos.NewRangeFromCoords(2, 22, 2, 35),
+			Path:    filepath.Join(libPath, "lib.libsonnet"),
+			LibPath: libPath,
+		},
+		{
+			Name:    "data.bin",
+			Range:   jpos.NewRangeFromCoords(3, 22, 3, 30),
+			Path:    filepath.Join(libPath, "data.bin"),
+			LibPath: libPath,
+		},
+		{
+			Name:  "missing.libsonnet",
+			Range: jpos.NewRangeFromCoords(4, 19, 4, 36),
+		},
+	}
+
+	assert.Equal(t, expected, got)
+}
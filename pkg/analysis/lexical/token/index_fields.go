@@ -0,0 +1,108 @@
+package token
+
+import (
+	"github.com/tminor/jsonnet-language-server/pkg/analysis/lexical/astext"
+	jpos "github.com/tminor/jsonnet-language-server/pkg/util/position"
+	"github.com/google/go-jsonnet/ast"
+	"github.com/pkg/errors"
+)
+
+// IndexFields returns the fields of the expression being indexed at a
+// position, e.g. `o.`, `(import "x.libsonnet").`, `foo(1).` or `arr[0].`.
+// The position is immediately after the dot. The expression is resolved
+// statically if possible. Otherwise it is evaluated.
+func IndexFields(source string, pos jpos.Position, nodeCache *NodeCache, config IdentifyConfig) ([]Field, error) {
+	node, sg, pi, err := findPartialIndex(source, pos, nodeCache, config)
+	if err != nil {
+		return nil, err
+	}
+
+	return indexFields(node, sg, pi, nodeCache, config)
+}
+
+// findPartialIndex finds the partial index which ends at a position.
+func findPartialIndex(source string, pos jpos.Position, nodeCache *NodeCache, config IdentifyConfig) (ast.Node, *scopeGraph, *astext.PartialIndex, error) {
+	dot := ast.Location{Line: pos.Line(), Column: pos.Column() - 1}
+	offset := sourceOffset(source, dot)
+	if offset >= len(source) || source[offset] != '.' {
+		return nil, nil, nil, errors.Errorf("position %s is not after an index", pos.String())
+	}
+
+	for _, candidate := range indexSources(config.path, source, offset) {
+		node, err := ReadSource(config.path, candidate, nil)
+		if err != nil {
+			continue
+		}
+
+		sg := scanScope(node, nodeCache)
+		pi := sg.partialIndexAt(dot)
+		if pi == nil {
+			continue
+		}
+
+		return node, sg, pi, nil
+	}
+
+	return nil, nil, nil, errors.Errorf("unable to find index at %s", pos.String())
+}
+
+// indexSources returns versions of source which could contain the partial
+// index at offset. The first version is the source itself. The second
+// truncates the source after the dot and closes unclosed brackets.
+func indexSources(filename, source string, offset int) []string {
+	sources := []string{source}
+
+	truncated := source[:offset+1]
+	tokens, err := Lex(filename, truncated)
+	if err != nil {
+		return sources
+	}
+
+	var open []*Token
+	for i := range tokens {
+		switch tokens[i].Kind {
+		case TokenBraceL, TokenBracketL, TokenParenL:
+			open = append(open, &tokens[i])
+		case TokenBraceR, TokenBracketR, TokenParenR:
+			if len(open) > 0 {
+				open = open[:len(open)-1]
+			}
+		}
+	}
+
+	return append(sources, closeBrackets(truncated, nil, open))
+}
+
+func indexFields(node ast.Node, sg *scopeGraph, pi *astext.PartialIndex, nodeCache *NodeCache, config IdentifyConfig) ([]Field, error) {
+	or := newObjectResolver(config.path, sg, config.jsonnetLibPaths, nodeCache)
+
+	layers, err := or.layers(sg, pi.Target)
+	if err == nil {
+		return or.fields(layers), nil
+	}
+
+	es, err := eval(node, pi.Target, nodeCache)
+	if err != nil {
+		return nil, errors.Wrap(err, "find scope for index")
+	}
+
+	scope := newScope(nodeCache)
+	scope.addEvalScope(es)
+
+	stub, err := buildEvalStub(pi.Target, scope)
+	if err != nil {
+		return nil, err
+	}
+
+	evaluated, err := evaluateNode(stub, config.VM())
+	if err != nil {
+		return nil, errors.Wrap(err, "evaluate index target")
+	}
+
+	layers, err = or.layers(nil, evaluated)
+	if err != nil {
+		return nil, err
+	}
+
+	return or.fields(layers), nil
+}
@@ -0,0 +1,108 @@
+package token
+
+import (
+	"testing"
+
+	jpos "github.com/tminor/jsonnet-language-server/pkg/util/position"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestIndexFields(t *testing.T) {
+	cases := []struct {
+		name     string
+		source   string
+		pos      jpos.Position
+		expected []string
+		isErr    bool
+	}{
+		{
+			name:     "variable",
+			source:   "local o = {a: 1, b:: 2}; o.",
+			pos:      jpos.New(1, 28),
+			expected: []string{"a", "b"},
+		},
+		{
+			name:     "nested index",
+			source:   "local o = {a: {c: 1}}; o.a.",
+			pos:      jpos.New(1, 28),
+			expected: []string{"c"},
+		},
+		{
+			name:     "index followed by source",
+			source:   "local o = {a: 1}; o.; o",
+			pos:      jpos.New(1, 21),
+			expected: []string{"a"},
+		},
+		{
+			name:     "self",
+			source:   "{a: 1, b: self.}",
+			pos:      jpos.New(1, 16),
+			expected: []string{"a", "b"},
+		},
+		{
+			name:     "super",
+			source:   "{a: 1, c:: 3} + {b: super.}",
+			pos:      jpos.New(1, 27),
+			expected: []string{"a", "c"},
+		},
+		{
+			name:     "dollar",
+			source:   "{a: 1, b: {c: $.}}",
+			pos:      jpos.New(1, 17),
+			expected: []string{"a", "b"},
+		},
+		{
+			name:     "function call",
+			source:   "local f(x) = {a: x}; f(1).",
+			pos:      jpos.New(1, 27),
+			expected: []string{"a"},
+		},
+		{
+			name:     "array index",
+			source:   "local arr = [{a: 1}]; arr[0].",
+			pos:      jpos.New(1, 30),
+			expected: []string{"a"},
+		},
+		{
+			name:     "import",
+			source:   `(import "index_fields.libsonnet").`,
+			pos:      jpos.New(1, 35),
+			expected: []string{"a", "b"},
+		},
+		{
+			name:     "field in import",
+			source:   `(import "index_fields.libsonnet").b.`,
+			pos:      jpos.New(1, 37),
+			expected: []string{"c"},
+		},
+		{
+			name:   "not an index",
+			source: "local o = {a: 1}; o",
+			pos:    jpos.New(1, 20),
+			isErr:  true,
+		},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			config, err := NewIdentifyConfig("testdata/file.jsonnet")
+			require.NoError(t, err)
+
+			fields, err := IndexFields(tc.source, tc.pos, NewNodeCache(), config)
+			if tc.isErr {
+				require.Error(t, err)
+				return
+			}
+
+			require.NoError(t, err)
+
+			var got []string
+			for _, field := range fields {
+				got = append(got, field.Name)
+			}
+
+			assert.Equal(t, tc.expected, got)
+		})
+	}
+}
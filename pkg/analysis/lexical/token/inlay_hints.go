@@ -0,0 +1,213 @@
+package token
+
+import (
+	"sort"
+	"strings"
+
+	jpos "github.com/tminor/jsonnet-language-server/pkg/util/position"
+	"github.com/google/go-jsonnet/ast"
+)
+
+const (
+	// maxInlayValueLength limits the length of local value hints. Longer
+	// values are not shown.
+	maxInlayValueLength = 30
+	// maxInlayValueNodes limits the size of expressions evaluated for local
+	// value hints.
+	maxInlayValueNodes = 20
+)
+
+// InlayHintKind is the kind of an inlay hint.
+type InlayHintKind int
+
+const (
+	// InlayHintParameter is the name of a parameter at a call argument.
+	InlayHintParameter InlayHintKind = iota
+	// InlayHintValue is the evaluated value of a local binding.
+	InlayHintValue
+)
+
+// InlayHintOptions configures which inlay hints are created.
+type InlayHintOptions struct {
+	// ParameterNames shows parameter names at positional call arguments.
+	ParameterNames bool
+	// LocalValues shows the evaluated value after local bindings which
+	// are cheap to compute.
+	LocalValues bool
+}
+
+// InlayHint is a hint shown inline with source.
+type InlayHint struct {
+	Position jpos.Position
+	Label    string
+	Kind     InlayHintKind
+}
+
+// InlayHints returns the inlay hints in a range of source.
+func InlayHints(source string, r jpos.Range, options InlayHintOptions, nodeCache *NodeCache, config IdentifyConfig) ([]InlayHint, error) {
+	node, err := ReadSource(config.path, source, nil)
+	if err != nil {
+		return nil, err
+	}
+
+	sg := scanScope(node, nodeCache)
+	or := newObjectResolver(config.path, sg, config.jsonnetLibPaths, nodeCache)
+
+	var hints []InlayHint
+
+	for n := range sg.parents {
+		switch n := n.(type) {
+		case *ast.Apply:
+			if options.ParameterNames {
+				hints = append(hints, parameterHints(or, sg, n, r)...)
+			}
+		case *ast.Local:
+			if options.LocalValues && !isDesugaredLocal(n) {
+				hints = append(hints, valueHints(node, sg, n, r, nodeCache, config)...)
+			}
+		}
+	}
+
+	sort.Slice(hints, func(i, j int) bool {
+		a, b := hints[i].Position.ToJsonnet(), hints[j].Position.ToJsonnet()
+		return locationBefore(a, b)
+	})
+
+	return hints, nil
+}
+
+// parameterHints names the parameters of positional arguments in a call.
+// Arguments which are variables with the same name as the parameter are
+// not hinted.
+func parameterHints(or *objectResolver, sg *scopeGraph, apply *ast.Apply, r jpos.Range) []InlayHint {
+	fn, _, err := or.function(sg, apply.Target)
+	if err != nil {
+		return nil
+	}
+
+	var params []ast.Identifier
+	params = append(params, fn.Parameters.Required...)
+	for _, param := range fn.Parameters.Optional {
+		params = append(params, param.Name)
+	}
+
+	var hints []InlayHint
+	for i, arg := range apply.Arguments.Positional {
+		if i >= len(params) {
+			break
+		}
+
+		if v, ok := arg.(*ast.Var); ok && v.Id == params[i] {
+			continue
+		}
+
+		begin := arg.Loc().Begin
+		if !inRange(r, begin) {
+			continue
+		}
+
+		hints = append(hints, InlayHint{
+			Position: jpos.FromJsonnetLocation(begin),
+			Label:    string(params[i]) + ":",
+			Kind:     InlayHintParameter,
+		})
+	}
+
+	return hints
+}
+
+// valueHints shows the evaluated values of a local's binds. Only binds
+// which are cheap to evaluate and have a short value are hinted.
+func valueHints(root ast.Node, sg *scopeGraph, local *ast.Local, r jpos.Range, nodeCache *NodeCache, config IdentifyConfig) []InlayHint {
+	var hints []InlayHint
+
+	for _, bind := range local.Binds {
+		if isLiteral(bind.Body) {
+			continue
+		}
+
+		budget := maxInlayValueNodes
+		if !isCheap(sg, bind.Body, &budget) {
+			continue
+		}
+
+		end := bind.Body.Loc().End
+		if !inRange(r, end) {
+			continue
+		}
+
+		value, err := evaluateInScope(root, bind.Body, bind.Body, nodeCache, config)
+		if err != nil || strings.Contains(value, "\n") || len(value) > maxInlayValueLength {
+			continue
+		}
+
+		hints = append(hints, InlayHint{
+			Position: jpos.FromJsonnetLocation(end),
+			Label:    "= " + value,
+			Kind:     InlayHintValue,
+		})
+	}
+
+	return hints
+}
+
+// isCheap returns true if a node is an expression which is cheap to
+// evaluate. Calls, imports, functions and objects are never cheap. A
+// variable is cheap if the value bound to it is cheap.
+func isCheap(sg *scopeGraph, n ast.Node, budget *int) bool {
+	*budget--
+	if *budget < 0 {
+		return false
+	}
+
+	switch n := n.(type) {
+	case *ast.LiteralBoolean, *ast.LiteralNull, *ast.LiteralNumber,
+		*ast.LiteralString:
+		return true
+	case *ast.Var:
+		decl, err := sg.declaration(n)
+		if err != nil {
+			return false
+		}
+
+		return isCheap(sg, decl, budget)
+	case *ast.Unary:
+		return isCheap(sg, n.Expr, budget)
+	case *ast.Binary:
+		return isCheap(sg, n.Left, budget) && isCheap(sg, n.Right, budget)
+	case *ast.Conditional:
+		return isCheap(sg, n.Cond, budget) &&
+			isCheap(sg, n.BranchTrue, budget) &&
+			isCheap(sg, n.BranchFalse, budget)
+	case *ast.Index:
+		return isCheap(sg, n.Target, budget) && isCheap(sg, n.Index, budget)
+	case *ast.Array:
+		for _, elem := range n.Elements {
+			if !isCheap(sg, elem, budget) {
+				return false
+			}
+		}
+		return true
+	default:
+		return false
+	}
+}
+
+func isLiteral(n ast.Node) bool {
+	switch n.(type) {
+	case *ast.LiteralBoolean, *ast.LiteralNull, *ast.LiteralNumber, *ast.LiteralString:
+		return true
+	default:
+		return false
+	}
+}
+
+// inRange returns true if a location is in a range. Locations without a
+// position, e.g. nodes created while desugaring, are never in range.
+func inRange(r jpos.Range, loc ast.Location) bool {
+	if loc.Line == 0 {
+		return false
+	}
+
+	return !locationBefore(loc, r.Start.ToJsonnet()) && !locationBefore(r.End.ToJsonnet(), loc)
+}
@@ -0,0 +1,87 @@
+package token
+
+import (
+	"testing"
+
+	jpos "github.com/tminor/jsonnet-language-server/pkg/util/position"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestInlayHints(t *testing.T) {
+	all := jpos.NewRangeFromCoords(1, 1, 100, 1)
+
+	cases := []struct {
+		name     string
+		source   string
+		r        jpos.Range
+		options  InlayHintOptions
+		expected []InlayHint
+	}{
+		{
+			name:    "parameter names",
+			source:  "local f(a, b=2) = a + b;\nf(1, 3)",
+			r:       all,
+			options: InlayHintOptions{ParameterNames: true},
+			expected: []InlayHint{
+				{Position: jpos.New(2, 3), Label: "a:", Kind: InlayHintParameter},
+				{Position: jpos.New(2, 6), Label: "b:", Kind: InlayHintParameter},
+			},
+		},
+		{
+			name:     "argument with parameter name",
+			source:   "local f(a) = a;\nlocal a = 1;\nf(a)",
+			r:        all,
+			options:  InlayHintOptions{ParameterNames: true},
+			expected: nil,
+		},
+		{
+			name:    "function in object",
+			source:  "local o = {f(x):: x};\no.f(1)",
+			r:       all,
+			options: InlayHintOptions{ParameterNames: true},
+			expected: []InlayHint{
+				{Position: jpos.New(2, 5), Label: "x:", Kind: InlayHintParameter},
+			},
+		},
+		{
+			name:    "outside of range",
+			source:  "local f(a) = a;\nf(1)",
+			r:       jpos.NewRangeFromCoords(1, 1, 1, 16),
+			options: InlayHintOptions{ParameterNames: true},
+		},
+		{
+			name:    "local values",
+			source:  "local a = 1;\nlocal b = a + 2;\nlocal c = std.length([1]);\nb",
+			r:       all,
+			options: InlayHintOptions{LocalValues: true},
+			expected: []InlayHint{
+				{Position: jpos.New(2, 16), Label: "= 3", Kind: InlayHintValue},
+			},
+		},
+		{
+			name:    "local values from expensive locals",
+			source:  "local lib = import 'lib.libsonnet';\nlocal f(x) = {a: x};\nlocal a = lib.a;\nlocal b = f(1).a;\nlocal c = a + b;\nc",
+			r:       all,
+			options: InlayHintOptions{LocalValues: true},
+		},
+		{
+			name:    "disabled",
+			source:  "local f(a) = a;\nlocal b = 1 + 2;\nf(b)",
+			r:       all,
+			options: InlayHintOptions{},
+		},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			config, err := NewIdentifyConfig("file.jsonnet")
+			require.NoError(t, err)
+
+			hints, err := InlayHints(tc.source, tc.r, tc.options, NewNodeCache(), config)
+			require.NoError(t, err)
+
+			assert.Equal(t, tc.expected, hints)
+		})
+	}
+}
@@ -0,0 +1,274 @@
+package token
+
+import (
+	jpos "github.com/tminor/jsonnet-language-server/pkg/util/position"
+	"github.com/google/go-jsonnet/ast"
+	"github.com/pkg/errors"
+)
+
+// FieldCompletion is a set of fields which can be set in an object literal.
+type FieldCompletion struct {
+	// Fields are fields in the objects being extended which have not been
+	// set in the object literal.
+	Fields []Field
+	// Range is the range of the partial field name at the position.
+	Range jpos.Range
+}
+
+// FieldCandidates returns the fields which can be overridden in an object
+// literal which extends another object, e.g. `base + { | }` or `base { | }`.
+// If the position is not at a field name in an object literal which extends
+// another object, it returns nil.
+func FieldCandidates(filename, source string, pos jpos.Position, libPaths []string, nc *NodeCache) (*FieldCompletion, error) {
+	tokens, err := Lex(filename, source)
+	if err != nil {
+		return nil, errors.Wrap(err, "lexing source")
+	}
+
+	cur := pos.ToJsonnet()
+
+	fk, ok := findFieldKey(tokens, cur)
+	if !ok {
+		return nil, nil
+	}
+
+	var sg *scopeGraph
+	var o *ast.DesugaredObject
+
+	for _, patched := range patchSource(source, fk) {
+		node, err := ReadSource(filename, patched, nil)
+		if err != nil {
+			continue
+		}
+
+		sg = scanScope(node, nc)
+		o = sg.objectAt(fk.brace.Loc.Begin)
+		if o != nil {
+			break
+		}
+	}
+
+	if o == nil {
+		return nil, errors.Errorf("unable to find object at %s", pos.String())
+	}
+
+	or := newObjectResolver(filename, sg, libPaths, nc)
+	base, err := or.super(sg, o)
+	if err != nil {
+		return nil, err
+	}
+
+	if len(base) == 0 {
+		return nil, nil
+	}
+
+	existing := make(map[string]bool)
+	for _, field := range o.Fields {
+		name, err := fieldName(field)
+		if err != nil {
+			continue
+		}
+		existing[name] = true
+	}
+
+	fc := &FieldCompletion{
+		Range: jpos.NewRange(pos, pos),
+	}
+
+	if fk.word != nil {
+		fc.Range = jpos.FromJsonnetRange(fk.word.Loc)
+	}
+
+	for _, field := range or.fields(base) {
+		if existing[field.Name] {
+			continue
+		}
+
+		fc.Fields = append(fc.Fields, field)
+	}
+
+	return fc, nil
+}
+
+// fieldKey describes a field key being typed in an object literal.
+type fieldKey struct {
+	// brace is the opening brace of the object.
+	brace *Token
+	// word is the partial field name at the position. It is nil if nothing
+	// has been typed.
+	word *Token
+	// open are the brackets which have not been closed before the position.
+	open []*Token
+	// after are the tokens after the position.
+	after []Token
+}
+
+// findFieldKey determines if a location is where a field name in an object
+// literal is expected.
+// nolint: gocyclo
+func findFieldKey(tokens Tokens, cur ast.Location) (fieldKey, bool) {
+	var fk fieldKey
+
+	var before []Token
+	for i := range tokens {
+		t := tokens[i]
+		if t.Kind == TokenEndOfFile || !locationBefore(t.Loc.Begin, cur) {
+			fk.after = tokens[i:]
+			break
+		}
+
+		if locationBefore(t.Loc.End, cur) || (t.Loc.End == cur && t.Kind != TokenIdentifier) {
+			before = append(before, t)
+			continue
+		}
+
+		// the token contains the location.
+		if t.Kind != TokenIdentifier {
+			return fk, false
+		}
+
+		fk.word = &tokens[i]
+		fk.after = tokens[i+1:]
+		break
+	}
+
+	var stack []int
+	for i, t := range before {
+		switch t.Kind {
+		case TokenBraceL, TokenBracketL, TokenParenL:
+			stack = append(stack, i)
+		case TokenBraceR, TokenBracketR, TokenParenR:
+			if len(stack) > 0 {
+				stack = stack[:len(stack)-1]
+			}
+		}
+	}
+
+	if len(stack) == 0 {
+		return fk, false
+	}
+
+	braceIndex := stack[len(stack)-1]
+	if before[braceIndex].Kind != TokenBraceL {
+		return fk, false
+	}
+
+	// the field being typed starts after the brace or the last comma in the
+	// object.
+	slotStart := braceIndex + 1
+	depth := 0
+	for i := braceIndex + 1; i < len(before); i++ {
+		switch before[i].Kind {
+		case TokenBraceL, TokenBracketL, TokenParenL:
+			depth++
+		case TokenBraceR, TokenBracketR, TokenParenR:
+			depth--
+		case TokenComma:
+			if depth == 0 {
+				slotStart = i + 1
+			}
+		}
+	}
+
+	if slotStart != len(before) {
+		return fk, false
+	}
+
+	fk.brace = &before[braceIndex]
+	for _, i := range stack {
+		fk.open = append(fk.open, &before[i])
+	}
+
+	return fk, true
+}
+
+// patchSource returns versions of source which can be parsed without the
+// partial field being typed. The first version removes the field. The second
+// truncates the source at the field and closes unclosed brackets.
+func patchSource(source string, fk fieldKey) []string {
+	if fk.word == nil {
+		return []string{source, closeBrackets(source, fk.after, fk.open)}
+	}
+
+	start := sourceOffset(source, fk.word.Loc.Begin)
+	end := sourceOffset(source, fk.word.Loc.End)
+
+	// if the word is the name of an existing field, remove the whole field.
+	if len(fk.after) > 0 && (fk.after[0].Kind == TokenOperator || fk.after[0].Kind == TokenParenL) {
+		depth := 0
+	loop:
+		for _, t := range fk.after {
+			switch t.Kind {
+			case TokenBraceL, TokenBracketL, TokenParenL:
+				depth++
+			case TokenBraceR, TokenBracketR, TokenParenR:
+				if depth == 0 {
+					end = sourceOffset(source, t.Loc.Begin)
+					break loop
+				}
+				depth--
+			case TokenComma:
+				if depth == 0 {
+					end = sourceOffset(source, t.Loc.End)
+					break loop
+				}
+			case TokenEndOfFile:
+				end = len(source)
+			}
+		}
+	}
+
+	return []string{
+		source[:start] + source[end:],
+		closeBrackets(source[:start], nil, fk.open),
+	}
+}
+
+// closeBrackets truncates source at the first token after a location and
+// closes open brackets.
+func closeBrackets(source string, after []Token, open []*Token) string {
+	if len(after) > 0 && after[0].Kind != TokenEndOfFile {
+		source = source[:sourceOffset(source, after[0].Loc.Begin)]
+	}
+
+	closers := map[TokenKind]string{
+		TokenBraceL:   "}",
+		TokenBracketL: "]",
+		TokenParenL:   ")",
+	}
+
+	for i := len(open) - 1; i >= 0; i-- {
+		source += closers[open[i].Kind]
+	}
+
+	return source
+}
+
+// locationBefore returns true if location a is before location b.
+func locationBefore(a, b ast.Location) bool {
+	if a.Line != b.Line {
+		return a.Line < b.Line
+	}
+
+	return a.Column < b.Column
+}
+
+// sourceOffset converts a location to a byte offset in source.
+func sourceOffset(source string, loc ast.Location) int {
+	line, column := 1, 1
+	for i := 0; i < len(source); i++ {
+		if line == loc.Line && column == loc.Column {
+			return i
+		}
+
+		if source[i] == '\n' {
+			line++
+			column = 1
+			continue
+		}
+
+		column++
+	}
+
+	return len(source)
+}
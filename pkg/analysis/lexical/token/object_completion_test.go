@@ -0,0 +1,107 @@
+package token
+
+import (
+	"testing"
+
+	jpos "github.com/tminor/jsonnet-language-server/pkg/util/position"
+	"github.com/google/go-jsonnet/ast"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestFieldCandidates(t *testing.T) {
+	base := "local base = {a: 1, b:: {c: 2}}; "
+
+	cases := []struct {
+		name     string
+		source   string
+		pos      jpos.Position
+		expected map[string]ast.ObjectFieldHide
+		r        jpos.Range
+		isNil    bool
+	}{
+		{
+			name:   "extend with plus",
+			source: base + "base + { }",
+			pos:    jpos.New(1, 43),
+			expected: map[string]ast.ObjectFieldHide{
+				"a": ast.ObjectFieldInherit,
+				"b": ast.ObjectFieldHidden,
+			},
+			r: jpos.NewRangeFromCoords(1, 43, 1, 43),
+		},
+		{
+			name:   "extend with brace",
+			source: base + "base { }",
+			pos:    jpos.New(1, 41),
+			expected: map[string]ast.ObjectFieldHide{
+				"a": ast.ObjectFieldInherit,
+				"b": ast.ObjectFieldHidden,
+			},
+			r: jpos.NewRangeFromCoords(1, 41, 1, 41),
+		},
+		{
+			name:   "partial field name",
+			source: base + "base + { a }",
+			pos:    jpos.New(1, 44),
+			expected: map[string]ast.ObjectFieldHide{
+				"a": ast.ObjectFieldInherit,
+				"b": ast.ObjectFieldHidden,
+			},
+			r: jpos.NewRangeFromCoords(1, 43, 1, 44),
+		},
+		{
+			name:   "existing fields are excluded",
+			source: base + "base + { a: 2, }",
+			pos:    jpos.New(1, 49),
+			expected: map[string]ast.ObjectFieldHide{
+				"b": ast.ObjectFieldHidden,
+			},
+			r: jpos.NewRangeFromCoords(1, 49, 1, 49),
+		},
+		{
+			name:   "unclosed object",
+			source: base + "base + { ",
+			pos:    jpos.New(1, 43),
+			expected: map[string]ast.ObjectFieldHide{
+				"a": ast.ObjectFieldInherit,
+				"b": ast.ObjectFieldHidden,
+			},
+			r: jpos.NewRangeFromCoords(1, 43, 1, 43),
+		},
+		{
+			name:   "position in field value",
+			source: base + "base + { a: }",
+			pos:    jpos.New(1, 46),
+			isNil:  true,
+		},
+		{
+			name:   "object does not extend another object",
+			source: "{ }",
+			pos:    jpos.New(1, 3),
+			isNil:  true,
+		},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			fc, err := FieldCandidates("file.jsonnet", tc.source, tc.pos, nil, NewNodeCache())
+			require.NoError(t, err)
+
+			if tc.isNil {
+				require.Nil(t, fc)
+				return
+			}
+
+			require.NotNil(t, fc)
+
+			got := make(map[string]ast.ObjectFieldHide)
+			for _, field := range fc.Fields {
+				got[field.Name] = field.Hide
+			}
+
+			assert.Equal(t, tc.expected, got)
+			assert.Equal(t, tc.r, fc.Range)
+		})
+	}
+}
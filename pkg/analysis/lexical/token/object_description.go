@@ -0,0 +1,107 @@
+package token
+
+import (
+	jpos "github.com/tminor/jsonnet-language-server/pkg/util/position"
+	"github.com/google/go-jsonnet/ast"
+	"github.com/pkg/errors"
+)
+
+const (
+	keywordSelf   = "self"
+	keywordSuper  = "super"
+	keywordDollar = "$"
+)
+
+// ObjectDescription describes the object `self`, `super` or `$` refers to.
+type ObjectDescription struct {
+	// Keyword is the keyword which refers to the object.
+	Keyword string
+	// Location is where the object literal containing the keyword is
+	// defined.
+	Location *jpos.Location
+	// Fields are the object's fields, including hidden fields.
+	Fields []Field
+	// Chain are the locations of the object literals the object is composed
+	// of, ordered from the base object. Objects which were evaluated rather
+	// than read from source are not included.
+	Chain []jpos.Location
+}
+
+// describeKeyword describes the object a `self`, `super` or `$` node refers
+// to.
+func describeKeyword(sg *scopeGraph, n ast.Node, nodeCache *NodeCache, config IdentifyConfig) (*ObjectDescription, error) {
+	or := newObjectResolver(config.path, sg, config.jsonnetLibPaths, nodeCache)
+
+	od := &ObjectDescription{}
+
+	var o *ast.DesugaredObject
+	var layers []objectLayer
+	var err error
+
+	switch n := n.(type) {
+	case *ast.Self:
+		od.Keyword = keywordSelf
+		o, layers, err = selfLayers(or, sg, n)
+	case *ast.Var:
+		decl, declErr := sg.declaration(n)
+		if declErr != nil {
+			return nil, declErr
+		}
+
+		self, ok := decl.(*ast.Self)
+		if !ok {
+			return nil, errors.Errorf("%q is not bound to self", n.Id)
+		}
+
+		od.Keyword = keywordDollar
+		o, layers, err = selfLayers(or, sg, self)
+	case *ast.SuperIndex:
+		od.Keyword = keywordSuper
+		o, err = sg.enclosingObject(n)
+		if err == nil {
+			layers, err = or.super(sg, o)
+		}
+	default:
+		return nil, errors.Errorf("%T does not refer to an object", n)
+	}
+
+	if err != nil {
+		return nil, err
+	}
+
+	loc := jpos.LocationFromJsonnet(*o.Loc())
+	od.Location = &loc
+	od.Fields = or.fields(layers)
+
+	for _, layer := range layers {
+		if layer.object == nil {
+			continue
+		}
+
+		od.Chain = append(od.Chain, jpos.LocationFromJsonnet(*layer.object.Loc()))
+	}
+
+	return od, nil
+}
+
+// selfLayers returns the object enclosing a `self` and the layers of the
+// composition it is part of.
+func selfLayers(or *objectResolver, sg *scopeGraph, n *ast.Self) (*ast.DesugaredObject, []objectLayer, error) {
+	o, err := sg.enclosingObject(n)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	layers, err := or.layers(sg, sg.composition(o))
+	if err != nil {
+		return nil, nil, err
+	}
+
+	return o, layers, nil
+}
+
+// isDollar returns true if a node is the `$` variable.
+func isDollar(n ast.Node) bool {
+	v, ok := n.(*ast.Var)
+	return ok && v.Id == ast.Identifier(keywordDollar)
+}
@@ -0,0 +1,541 @@
+package token
+
+import (
+	"io/ioutil"
+
+	"github.com/tminor/jsonnet-language-server/pkg/analysis/lexical/astext"
+	jpos "github.com/tminor/jsonnet-language-server/pkg/util/position"
+	"github.com/google/go-jsonnet/ast"
+	"github.com/pkg/errors"
+)
+
+const (
+	// maxResolveDepth limits how deep the object resolver will follow
+	// locals, indexes and imports.
+	maxResolveDepth = 64
+)
+
+// Field is a field in an object. The object may be composed from multiple
+// object literals using `+`.
+type Field struct {
+	Name      string
+	Hide      ast.ObjectFieldHide
+	PlusSuper bool
+	Node      ast.Node
+	Location  jpos.Location
+}
+
+// IsHidden returns true if the field is hidden.
+func (f *Field) IsHidden() bool {
+	return f.Hide == ast.ObjectFieldHidden
+}
+
+// IsFunction returns true if the field is a function.
+func (f *Field) IsFunction() bool {
+	_, ok := f.Node.(*ast.Function)
+	return ok
+}
+
+// Visibility is the field's visibility operator.
+func (f *Field) Visibility() string {
+	return astext.ObjectFieldVisibility(f.Hide)
+}
+
+// Detail is a short description of the field's value.
+func (f *Field) Detail() string {
+	switch f.Node.(type) {
+	case *ast.DesugaredObject, *ast.Binary:
+		return "(object)"
+	default:
+		return astext.TokenName(f.Node)
+	}
+}
+
+// objectLayer is an object which contributes fields to a composed object.
+// Layers are either object literals from source or objects which were
+// evaluated and stored in the node cache.
+type objectLayer struct {
+	object    *ast.DesugaredObject
+	evaluated *ast.Object
+	graph     *scopeGraph
+}
+
+// fields returns the fields defined in the layer.
+func (l objectLayer) fields() []Field {
+	var fields []Field
+
+	if l.evaluated != nil {
+		for _, field := range l.evaluated.Fields {
+			var name string
+			switch field.Kind {
+			case ast.ObjectFieldID:
+				if field.Id == nil {
+					continue
+				}
+				name = string(*field.Id)
+			case ast.ObjectFieldStr:
+				name = astext.TokenValue(field.Expr1)
+			default:
+				continue
+			}
+
+			fields = append(fields, Field{
+				Name:      name,
+				Hide:      field.Hide,
+				PlusSuper: field.SuperSugar,
+				Node:      field.Expr2,
+			})
+		}
+
+		return fields
+	}
+
+	for _, field := range l.object.Fields {
+		name, err := fieldName(field)
+		if err != nil {
+			continue
+		}
+
+		f := Field{
+			Name:      name,
+			Hide:      field.Hide,
+			PlusSuper: field.PlusSuper,
+			Node:      fieldBody(field),
+		}
+
+		if loc, ok := l.object.FieldLocs[name]; ok {
+			f.Location = jpos.LocationFromJsonnet(loc)
+		}
+
+		fields = append(fields, f)
+	}
+
+	return fields
+}
+
+// field returns a field in the layer by name.
+func (l objectLayer) field(name string) (Field, bool) {
+	for _, f := range l.fields() {
+		if f.Name == name {
+			return f, true
+		}
+	}
+
+	return Field{}, false
+}
+
+// objectResolver statically resolves nodes to the object literals they are
+// composed of. It follows locals, indexes, self, super and imports.
+type objectResolver struct {
+	libPaths   []string
+	nodeCache  *NodeCache
+	graphs     map[string]*scopeGraph
+	graphFiles map[*scopeGraph]string
+	depth      int
+	// branches chooses the branch of each conditional when it is set.
+	// Otherwise, the first branch which resolves is used.
+	branches *branchChoice
+	// partial is set when a node is resolved from the result of a function
+	// or when part of a field's value can't be resolved.
+	partial bool
+}
+
+// branchChoice chooses the branches followed through conditionals, so each
+// object a node can be is resolved in turn.
+type branchChoice struct {
+	// falseBranch is true for the conditionals whose false branch is
+	// followed.
+	falseBranch map[*ast.Conditional]bool
+	// followed are the conditionals which were followed in order.
+	followed []*ast.Conditional
+}
+
+// branch returns the branch to follow through a conditional.
+func (bc *branchChoice) branch(n *ast.Conditional) ast.Node {
+	seen := false
+	for _, c := range bc.followed {
+		if c == n {
+			seen = true
+			break
+		}
+	}
+
+	if !seen {
+		bc.followed = append(bc.followed, n)
+	}
+
+	if bc.falseBranch[n] {
+		return n.BranchFalse
+	}
+
+	return n.BranchTrue
+}
+
+func newObjectResolver(filename string, sg *scopeGraph, libPaths []string, nc *NodeCache) *objectResolver {
+	return &objectResolver{
+		libPaths:   libPaths,
+		nodeCache:  nc,
+		graphs:     map[string]*scopeGraph{filename: sg},
+		graphFiles: map[*scopeGraph]string{sg: filename},
+	}
+}
+
+// layers returns the object literals a node is composed of. Layers are
+// ordered from left to right, so later layers override earlier ones.
+// nolint: gocyclo
+func (or *objectResolver) layers(sg *scopeGraph, n ast.Node) ([]objectLayer, error) {
+	or.depth++
+	defer func() { or.depth-- }()
+
+	if or.depth > maxResolveDepth {
+		return nil, errors.New("object is nested too deeply to resolve")
+	}
+
+	switch n := n.(type) {
+	case *ast.DesugaredObject:
+		return []objectLayer{{object: n, graph: sg}}, nil
+	case *ast.Object:
+		return []objectLayer{{evaluated: n, graph: sg}}, nil
+	case *ast.Binary:
+		if n.Op != ast.BopPlus {
+			return nil, errors.Errorf("binary operator %s does not create an object", n.Op)
+		}
+
+		left, err := or.layers(sg, n.Left)
+		if err != nil {
+			return nil, err
+		}
+
+		right, err := or.layers(sg, n.Right)
+		if err != nil {
+			return nil, err
+		}
+
+		return append(left, right...), nil
+	case *ast.Local:
+		return or.layers(sg, n.Body)
+	case *ast.Var:
+		decl, err := sg.declaration(n)
+		if err != nil {
+			return nil, err
+		}
+
+		return or.layers(sg, decl)
+	case *ast.Self:
+		o, err := sg.enclosingObject(n)
+		if err != nil {
+			return nil, err
+		}
+
+		return or.layers(sg, sg.composition(o))
+	case *ast.SuperIndex:
+		o, err := sg.enclosingObject(n)
+		if err != nil {
+			return nil, err
+		}
+
+		super, err := or.super(sg, o)
+		if err != nil {
+			return nil, err
+		}
+
+		// a super index without an index is `super` itself.
+		if n.Index == nil {
+			return super, nil
+		}
+
+		name, ok := n.Index.(*ast.LiteralString)
+		if !ok {
+			return nil, errors.New("super index is not a string")
+		}
+
+		return or.fieldLayers(super, name.Value)
+	case *ast.Index:
+		name, ok := n.Index.(*ast.LiteralString)
+		if !ok {
+			return nil, errors.New("index is not a string")
+		}
+
+		target, err := or.layers(sg, n.Target)
+		if err != nil {
+			return nil, err
+		}
+
+		return or.fieldLayers(target, name.Value)
+	case *ast.Import:
+		g, err := or.importGraph(sg, n.File.Value)
+		if err != nil {
+			node, cacheErr := or.cachedImport(n.File.Value)
+			if cacheErr != nil {
+				return nil, err
+			}
+
+			return or.layers(nil, node)
+		}
+
+		return or.layers(g, g.root)
+	case *ast.Apply:
+		or.partial = true
+
+		fn, g, err := or.function(sg, n.Target)
+		if err != nil {
+			return nil, err
+		}
+
+		return or.layers(g, fn.Body)
+	case *ast.Conditional:
+		if or.branches != nil {
+			return or.layers(sg, or.branches.branch(n))
+		}
+
+		layers, err := or.layers(sg, n.BranchTrue)
+		if err == nil {
+			return layers, nil
+		}
+
+		return or.layers(sg, n.BranchFalse)
+	case nil:
+		return nil, errors.New("unable to resolve a missing node to an object")
+	default:
+		return nil, errors.Errorf("unable to resolve a %T to an object", n)
+	}
+}
+
+// super returns the layers to the left of an object in the composition
+// it is part of.
+func (or *objectResolver) super(sg *scopeGraph, o *ast.DesugaredObject) ([]objectLayer, error) {
+	var layers []objectLayer
+
+	var cur ast.Node = o
+	for {
+		b, ok := sg.parentOf(cur).(*ast.Binary)
+		if !ok || b.Op != ast.BopPlus {
+			break
+		}
+
+		if b.Right == cur {
+			left, err := or.layers(sg, b.Left)
+			if err != nil {
+				return nil, err
+			}
+
+			layers = append(left, layers...)
+		}
+
+		cur = b
+	}
+
+	return layers, nil
+}
+
+// fieldLayers returns the layers for the value of a field in a composed
+// object. Fields defined with `+:` are merged with the fields they override.
+func (or *objectResolver) fieldLayers(layers []objectLayer, name string) ([]objectLayer, error) {
+	var out []objectLayer
+	found := false
+	incomplete := false
+	var lastErr error
+
+	for _, layer := range layers {
+		field, ok := layer.field(name)
+		if !ok {
+			continue
+		}
+
+		found = true
+
+		fieldLayers, err := or.layers(layer.graph, field.Node)
+		if err != nil {
+			incomplete = true
+			lastErr = err
+			if !field.PlusSuper {
+				out = nil
+			}
+			continue
+		}
+
+		lastErr = nil
+		if field.PlusSuper {
+			out = append(out, fieldLayers...)
+		} else {
+			out = fieldLayers
+			incomplete = false
+		}
+	}
+
+	if !found {
+		return nil, errors.Errorf("field %q does not exist", name)
+	}
+
+	if len(out) == 0 && lastErr != nil {
+		return nil, lastErr
+	}
+
+	// the value is missing the layers which couldn't be resolved.
+	if incomplete {
+		or.partial = true
+	}
+
+	return out, nil
+}
+
+// value resolves a node to the node which defines its value.
+func (or *objectResolver) value(sg *scopeGraph, n ast.Node) (ast.Node, *scopeGraph, error) {
+	or.depth++
+	defer func() { or.depth-- }()
+
+	if or.depth > maxResolveDepth {
+		return nil, nil, errors.New("value is nested too deeply to resolve")
+	}
+
+	switch n := n.(type) {
+	case *ast.Var:
+		decl, err := sg.declaration(n)
+		if err != nil {
+			return nil, nil, err
+		}
+
+		return or.value(sg, decl)
+	case *ast.Local:
+		return or.value(sg, n.Body)
+	case *ast.Import:
+		g, err := or.importGraph(sg, n.File.Value)
+		if err != nil {
+			return nil, nil, err
+		}
+
+		return or.value(g, g.root)
+	case *ast.Index:
+		name, ok := n.Index.(*ast.LiteralString)
+		if !ok {
+			return nil, nil, errors.New("index is not a string")
+		}
+
+		layers, err := or.layers(sg, n.Target)
+		if err != nil {
+			return nil, nil, err
+		}
+
+		for i := len(layers) - 1; i >= 0; i-- {
+			field, ok := layers[i].field(name.Value)
+			if !ok {
+				continue
+			}
+
+			return or.value(layers[i].graph, field.Node)
+		}
+
+		return nil, nil, errors.Errorf("field %q does not exist", name.Value)
+	default:
+		return n, sg, nil
+	}
+}
+
+// function resolves a node to a function.
+func (or *objectResolver) function(sg *scopeGraph, n ast.Node) (*ast.Function, *scopeGraph, error) {
+	v, g, err := or.value(sg, n)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	fn, ok := v.(*ast.Function)
+	if !ok {
+		return nil, nil, errors.Errorf("expected a function; it was %T", v)
+	}
+
+	return fn, g, nil
+}
+
+// importGraph parses an imported file and returns its scope graph.
+func (or *objectResolver) importGraph(sg *scopeGraph, name string) (*scopeGraph, error) {
+	path, err := resolveImport(or.graphFiles[sg], name, or.libPaths)
+	if err != nil {
+		return nil, err
+	}
+
+	if g, ok := or.graphs[path]; ok {
+		return g, nil
+	}
+
+	/* #nosec */
+	source, err := ioutil.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+
+	node, err := ReadSource(path, string(source), nil)
+	if err != nil {
+		return nil, errors.Wrapf(err, "reading import %q", name)
+	}
+
+	g := scanScope(node, or.nodeCache)
+	or.graphs[path] = g
+	or.graphFiles[g] = path
+
+	return g, nil
+}
+
+// cachedImport returns the evaluated node for an import from the node cache.
+func (or *objectResolver) cachedImport(name string) (ast.Node, error) {
+	if or.nodeCache == nil {
+		return nil, errors.Errorf("import %q is not cached", name)
+	}
+
+	ne, err := or.nodeCache.Get(name)
+	if err != nil {
+		return nil, err
+	}
+
+	return ne.Node, nil
+}
+
+// fields returns the fields defined by a set of layers. Fields are
+// returned in the order they are first defined.
+func (or *objectResolver) fields(layers []objectLayer) []Field {
+	var fields []Field
+	seen := make(map[string]int)
+
+	for _, layer := range layers {
+		for _, f := range layer.fields() {
+			i, ok := seen[f.Name]
+			if !ok {
+				seen[f.Name] = len(fields)
+				fields = append(fields, f)
+				continue
+			}
+
+			if f.Hide == ast.ObjectFieldInherit {
+				f.Hide = fields[i].Hide
+			}
+			fields[i] = f
+		}
+	}
+
+	return fields
+}
+
+// fieldBody returns the body of a field without the locals added when
+// desugaring object locals and `$`.
+func fieldBody(field ast.DesugaredObjectField) ast.Node {
+	body := field.Body
+	for {
+		local, ok := body.(*ast.Local)
+		if !ok || !isDesugaredLocal(local) {
+			return body
+		}
+
+		body = local.Body
+	}
+}
+
+// isDesugaredLocal returns true if a local was created by the desugarer.
+// Binds created by the desugarer do not have a location.
+func isDesugaredLocal(local *ast.Local) bool {
+	for _, bind := range local.Binds {
+		if bind.VarLoc.Begin.Line != 0 {
+			return false
+		}
+	}
+
+	return len(local.Binds) > 0
+}
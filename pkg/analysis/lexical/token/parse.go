@@ -414,8 +414,9 @@ func (p *mParser) parse(prec precedence) (ast.Node, error) {
 				}
 
 			case TokenDot:
-				fieldID, err := p.popExpect(TokenIdentifier)
-				if err != nil {
+				// leave the token after the dot so the remaining source can
+				// be parsed.
+				if p.peek().Kind != TokenIdentifier {
 					cur := p.peek()
 					loc := locFromTokens(cur, cur)
 					p.publishDiag("expected field id", cur.Loc)
@@ -424,6 +425,7 @@ func (p *mParser) parse(prec precedence) (ast.Node, error) {
 						Target:   lhs,
 					}, nil
 				}
+				fieldID := p.pop()
 				id := ast.Identifier(fieldID.Data)
 				lhs = &ast.Index{
 					NodeBase: ast.NewNodeBaseLoc(locFromTokens(begin, fieldID)),
@@ -1158,6 +1160,18 @@ func (p *mParser) parseTerminal() (ast.Node, error) {
 		var id *ast.Identifier
 		switch next.Kind {
 		case TokenDot:
+			// a bare super is kept so the fields of super can be
+			// completed.
+			if p.peek().Kind != TokenIdentifier {
+				cur := p.peek()
+				p.publishDiag("expected field id", cur.Loc)
+				return &astext.PartialIndex{
+					NodeBase: ast.NewNodeBaseLoc(locFromTokens(cur, cur)),
+					Target: &ast.SuperIndex{
+						NodeBase: ast.NewNodeBaseLoc(tok.Loc),
+					},
+				}, nil
+			}
 			fieldID, err := p.popExpect(TokenIdentifier)
 			if err != nil {
 				return nil, err
@@ -0,0 +1,103 @@
+package token
+
+import (
+	"github.com/tminor/jsonnet-language-server/pkg/analysis/lexical/astext"
+	jpos "github.com/tminor/jsonnet-language-server/pkg/util/position"
+	"github.com/google/go-jsonnet/ast"
+)
+
+// FieldDefinition is a definition of a field in one of the objects a
+// composed object is made from.
+type FieldDefinition struct {
+	// Location is where the field is defined.
+	Location jpos.Location
+	// Operator is how the field is defined, e.g. `:` or `+:`.
+	Operator string
+	// Static is a description of the value this definition contributes,
+	// found without evaluating it. It is not the merged value.
+	Static string
+	// Overridden is true if a later definition replaces the value with `:`.
+	Overridden bool
+}
+
+// provenanceOf returns the definitions of the field at a node in the objects
+// it is composed from. Nothing is returned unless the field is defined in
+// more than one object.
+func provenanceOf(sg *scopeGraph, n ast.Node, pos jpos.Position, nodeCache *NodeCache, config IdentifyConfig) []FieldDefinition {
+	or := newObjectResolver(config.path, sg, config.jsonnetLibPaths, nodeCache)
+
+	switch n := n.(type) {
+	case *ast.Index:
+		name, ok := n.Index.(*ast.LiteralString)
+		if !ok {
+			return nil
+		}
+
+		layers, err := or.layers(sg, n.Target)
+		if err != nil {
+			return nil
+		}
+
+		return fieldProvenance(layers, name.Value)
+	case *ast.DesugaredObject:
+		// the position is in a field name.
+		name, _, err := fieldNameAt(n, pos)
+		if err != nil {
+			return nil
+		}
+
+		layers, err := or.layers(sg, sg.composition(n))
+		if err != nil {
+			return nil
+		}
+
+		return fieldProvenance(layers, name)
+	default:
+		return nil
+	}
+}
+
+// fieldProvenance returns the definitions of a field in layers in the order
+// they are applied. Every definition is returned. Definitions which are
+// replaced by a later definition with `:` are marked as overridden. Layers
+// which were evaluated don't have locations and are skipped.
+func fieldProvenance(layers []objectLayer, name string) []FieldDefinition {
+	var definitions []FieldDefinition
+	count := 0
+
+	for _, layer := range layers {
+		field, ok := layer.field(name)
+		if !ok {
+			continue
+		}
+
+		count++
+
+		if !field.PlusSuper {
+			for i := range definitions {
+				definitions[i].Overridden = true
+			}
+		}
+
+		if field.Location.URI() == "" {
+			continue
+		}
+
+		operator := field.Visibility()
+		if field.PlusSuper {
+			operator = "+" + operator
+		}
+
+		definitions = append(definitions, FieldDefinition{
+			Location: field.Location,
+			Operator: operator,
+			Static:   astext.TokenName(field.Node),
+		})
+	}
+
+	if count < 2 {
+		return nil
+	}
+
+	return definitions
+}
@@ -0,0 +1,90 @@
+package token
+
+import (
+	"testing"
+
+	jpos "github.com/tminor/jsonnet-language-server/pkg/util/position"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const provenanceSource = "local base = { replicas: 1, spec: { a: 1 } };\n" +
+	"local env = base + { replicas: 2, spec+: { b: 2 } };\n" +
+	"local app = env + { spec+: { c: 3 } };\n" +
+	"[app.replicas, app.spec, base.spec, (app + { spec: {} }).spec]"
+
+func TestDescribe_provenance(t *testing.T) {
+	type definition struct {
+		start      jpos.Position
+		operator   string
+		overridden bool
+	}
+
+	cases := []struct {
+		name     string
+		pos      jpos.Position
+		expected []definition
+	}{
+		{
+			name: "overridden field",
+			pos:  jpos.New(4, 6),
+			expected: []definition{
+				{start: jpos.New(1, 16), operator: ":", overridden: true},
+				{start: jpos.New(2, 22), operator: ":"},
+			},
+		},
+		{
+			name: "merged field",
+			pos:  jpos.New(4, 20),
+			expected: []definition{
+				{start: jpos.New(1, 29), operator: ":"},
+				{start: jpos.New(2, 35), operator: "+:"},
+				{start: jpos.New(3, 21), operator: "+:"},
+			},
+		},
+		{
+			name: "field name",
+			pos:  jpos.New(3, 22),
+			expected: []definition{
+				{start: jpos.New(1, 29), operator: ":"},
+				{start: jpos.New(2, 35), operator: "+:"},
+				{start: jpos.New(3, 21), operator: "+:"},
+			},
+		},
+		{
+			name: "defined once",
+			pos:  jpos.New(4, 32),
+		},
+		{
+			name: "merged field overridden",
+			pos:  jpos.New(4, 58),
+			expected: []definition{
+				{start: jpos.New(1, 29), operator: ":", overridden: true},
+				{start: jpos.New(2, 35), operator: "+:", overridden: true},
+				{start: jpos.New(3, 21), operator: "+:", overridden: true},
+				{start: jpos.New(4, 46), operator: ":"},
+			},
+		},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			config, err := NewIdentifyConfig("file.jsonnet")
+			require.NoError(t, err)
+
+			d, err := Describe(provenanceSource, tc.pos, NewNodeCache(), config)
+			require.NoError(t, err)
+
+			var got []definition
+			for _, fd := range d.Provenance {
+				got = append(got, definition{
+					start:      fd.Location.Range().Start,
+					operator:   fd.Operator,
+					overridden: fd.Overridden,
+				})
+			}
+
+			assert.Equal(t, tc.expected, got)
+		})
+	}
+}
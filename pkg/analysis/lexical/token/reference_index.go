@@ -0,0 +1,331 @@
+package token
+
+import (
+	"sort"
+
+	jpos "github.com/tminor/jsonnet-language-server/pkg/util/position"
+	"github.com/google/go-jsonnet/ast"
+)
+
+// referenceIndex connects declarations to the identifiers which refer to
+// them. Declarations are locals, function parameters and object fields.
+// References are found in a single file, but the fields they refer to can be
+// declared in the files it imports.
+type referenceIndex struct {
+	filename string
+	// declarations are the declarations in the file.
+	declarations []jpos.Location
+	// locals are the locals and function parameters declared in the file.
+	locals map[jpos.Location]bool
+	// references are the references in the file to each declaration.
+	references map[jpos.Location][]jpos.Location
+	// overrides are the other declarations of a field in objects which are
+	// composed with `+`.
+	overrides map[jpos.Location]map[jpos.Location]bool
+	// bases are the declarations of a field in the objects to the left of
+	// the object declaring it. The field overrides or merges with them.
+	bases map[jpos.Location]map[jpos.Location]bool
+}
+
+func newReferenceIndex(filename string, sg *scopeGraph, libPaths []string, nodeCache *NodeCache) *referenceIndex {
+	ri := &referenceIndex{
+		filename:   filename,
+		locals:     make(map[jpos.Location]bool),
+		references: make(map[jpos.Location][]jpos.Location),
+		overrides:  make(map[jpos.Location]map[jpos.Location]bool),
+		bases:      make(map[jpos.Location]map[jpos.Location]bool),
+	}
+
+	for _, loc := range sg.declarations {
+		decl := ri.location(loc)
+		ri.declarations = append(ri.declarations, decl)
+		ri.locals[decl] = true
+	}
+
+	for begin, refs := range sg.references {
+		decl := ri.location(sg.declarations[begin])
+		for _, ref := range refs {
+			ri.references[decl] = append(ri.references[decl], ri.location(ref))
+		}
+	}
+
+	or := newObjectResolver(filename, sg, libPaths, nodeCache)
+
+	for n := range sg.parents {
+		switch n := n.(type) {
+		case *ast.DesugaredObject:
+			for _, field := range n.Fields {
+				name, err := fieldName(field)
+				if err != nil {
+					continue
+				}
+
+				if loc, ok := n.FieldLocs[name]; ok && loc.Begin.Line != 0 {
+					ri.declarations = append(ri.declarations, ri.location(loc))
+				}
+			}
+		case *ast.Binary:
+			// only the outermost object in a composition is linked.
+			if b, ok := sg.parentOf(n).(*ast.Binary); n.Op != ast.BopPlus || (ok && b.Op == ast.BopPlus) {
+				continue
+			}
+
+			layers, err := or.layers(sg, n)
+			if err != nil {
+				continue
+			}
+
+			ri.linkOverrides(or, layers, 0)
+		case *ast.Index:
+			name, ok := n.Index.(*ast.LiteralString)
+			if !ok || n.Loc().End.Line == 0 {
+				continue
+			}
+
+			r, ok := indexedNameRange(*n.Loc(), name)
+			if !ok {
+				continue
+			}
+
+			layers, err := or.layers(sg, n.Target)
+			if err != nil {
+				continue
+			}
+
+			ri.addFieldReference(or, layers, name.Value, r)
+		case *ast.SuperIndex:
+			name, ok := n.Index.(*ast.LiteralString)
+			if !ok || n.Loc().End.Line == 0 {
+				continue
+			}
+
+			r, ok := superIndexNameRange(*n.Loc(), name)
+			if !ok {
+				continue
+			}
+
+			o, err := sg.enclosingObject(n)
+			if err != nil {
+				continue
+			}
+
+			layers, err := or.super(sg, o)
+			if err != nil {
+				continue
+			}
+
+			ri.addFieldReference(or, layers, name.Value, r)
+		}
+	}
+
+	return ri
+}
+
+// addFieldReference adds the range of a field name in an index as a
+// reference to every declaration of the field in layers.
+func (ri *referenceIndex) addFieldReference(or *objectResolver, layers []objectLayer, name string, r ast.LocationRange) {
+	decls := fieldDeclarations(or, layers, name)
+	if len(decls) == 0 {
+		return
+	}
+
+	ref := ri.location(r)
+	for _, decl := range decls {
+		ri.references[decl] = append(ri.references[decl], ref)
+	}
+
+	ri.link(decls)
+}
+
+// linkOverrides links the declarations of fields which are declared in more
+// than one layer of a composed object. The values of fields declared with
+// `+:` are merged, so their fields are linked as well.
+func (ri *referenceIndex) linkOverrides(or *objectResolver, layers []objectLayer, depth int) {
+	if depth > maxResolveDepth {
+		return
+	}
+
+	for _, field := range or.fields(layers) {
+		decls := fieldDeclarations(or, layers, field.Name)
+		if len(decls) < 2 {
+			continue
+		}
+
+		ri.link(decls)
+		ri.linkBases(decls)
+
+		if !field.PlusSuper {
+			continue
+		}
+
+		fieldLayers, err := or.fieldLayers(layers, field.Name)
+		if err != nil {
+			continue
+		}
+
+		ri.linkOverrides(or, fieldLayers, depth+1)
+	}
+}
+
+// link records that declarations declare the same field.
+func (ri *referenceIndex) link(decls []jpos.Location) {
+	for _, decl := range decls {
+		for _, other := range decls {
+			if decl == other {
+				continue
+			}
+
+			if ri.overrides[decl] == nil {
+				ri.overrides[decl] = make(map[jpos.Location]bool)
+			}
+
+			ri.overrides[decl][other] = true
+		}
+	}
+}
+
+// linkBases records that each declaration overrides the declarations before
+// it. Declarations are ordered from left to right.
+func (ri *referenceIndex) linkBases(decls []jpos.Location) {
+	for i := 1; i < len(decls); i++ {
+		if ri.bases[decls[i]] == nil {
+			ri.bases[decls[i]] = make(map[jpos.Location]bool)
+		}
+
+		for _, base := range decls[:i] {
+			if base != decls[i] {
+				ri.bases[decls[i]][base] = true
+			}
+		}
+	}
+}
+
+// at returns the declaration which is declared at a position or which is
+// referred to at a position.
+func (ri *referenceIndex) at(pos jpos.Position) (jpos.Location, bool) {
+	for _, decl := range ri.declarations {
+		if pos.IsInJsonnetRange(decl.ToJsonnet()) {
+			return decl, true
+		}
+	}
+
+	// a reference to a field refers to every declaration of the field, so
+	// the first one is picked to make the result stable.
+	var found []jpos.Location
+	for decl, refs := range ri.references {
+		for _, ref := range refs {
+			if pos.IsInJsonnetRange(ref.ToJsonnet()) {
+				found = append(found, decl)
+				break
+			}
+		}
+	}
+
+	if len(found) == 0 {
+		return jpos.Location{}, false
+	}
+
+	sortLocations(found)
+
+	return found[0], true
+}
+
+// declarationsOf returns a declaration and the declarations which override
+// it or which it overrides.
+func (ri *referenceIndex) declarationsOf(decl jpos.Location) []jpos.Location {
+	locations := []jpos.Location{decl}
+	for other := range ri.overrides[decl] {
+		locations = append(locations, other)
+	}
+
+	sortLocations(locations)
+
+	return locations
+}
+
+// referencesTo returns the locations which refer to a declaration or to the
+// declarations which override it.
+func (ri *referenceIndex) referencesTo(decl jpos.Location) []jpos.Location {
+	var locations []jpos.Location
+	seen := make(map[jpos.Location]bool)
+
+	for _, d := range ri.declarationsOf(decl) {
+		for _, ref := range ri.references[d] {
+			if seen[ref] {
+				continue
+			}
+
+			seen[ref] = true
+			locations = append(locations, ref)
+		}
+	}
+
+	sortLocations(locations)
+
+	return locations
+}
+
+// basesOf returns the declarations a field declaration overrides or merges
+// with.
+func (ri *referenceIndex) basesOf(decl jpos.Location) []jpos.Location {
+	var locations []jpos.Location
+	for base := range ri.bases[decl] {
+		locations = append(locations, base)
+	}
+
+	sortLocations(locations)
+
+	return locations
+}
+
+// overridesOf returns the declarations which override or merge with a field
+// declaration.
+func (ri *referenceIndex) overridesOf(decl jpos.Location) []jpos.Location {
+	var locations []jpos.Location
+	for override, bases := range ri.bases {
+		if bases[decl] {
+			locations = append(locations, override)
+		}
+	}
+
+	sortLocations(locations)
+
+	return locations
+}
+
+func (ri *referenceIndex) location(r ast.LocationRange) jpos.Location {
+	return jpos.NewLocation(ri.filename, jpos.FromJsonnetRange(r))
+}
+
+// fieldDeclarations returns the locations where a field is declared in
+// layers. Layers which were evaluated don't have locations.
+func fieldDeclarations(or *objectResolver, layers []objectLayer, name string) []jpos.Location {
+	var locations []jpos.Location
+	for _, layer := range layers {
+		if layer.object == nil {
+			continue
+		}
+
+		loc, ok := layer.object.FieldLocs[name]
+		if !ok || loc.Begin.Line == 0 {
+			continue
+		}
+
+		filename := or.graphFiles[layer.graph]
+		locations = append(locations, jpos.NewLocation(filename, jpos.FromJsonnetRange(loc)))
+	}
+
+	return locations
+}
+
+// sortLocations sorts locations by file and then by where they begin.
+func sortLocations(locations []jpos.Location) {
+	sort.Slice(locations, func(i, j int) bool {
+		a, b := locations[i].ToJsonnet(), locations[j].ToJsonnet()
+		if a.FileName != b.FileName {
+			return a.FileName < b.FileName
+		}
+
+		return locationBefore(a.Begin, b.Begin)
+	})
+}
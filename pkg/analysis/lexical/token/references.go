@@ -0,0 +1,95 @@
+package token
+
+import (
+	"context"
+
+	jpos "github.com/tminor/jsonnet-language-server/pkg/util/position"
+	opentracing "github.com/opentracing/opentracing-go"
+)
+
+// ReferenceSet is the declarations of an identifier and the references to
+// it.
+type ReferenceSet struct {
+	// Declarations are where the identifier is declared. A field can be
+	// declared in more than one object when objects are composed with `+`,
+	// and those objects can be in other files.
+	Declarations []jpos.Location
+	// References are the identifiers which refer to the declarations.
+	References []jpos.Location
+	// Local is true if the identifier is a local or a function parameter.
+	// They can only be referenced in the file they are declared in.
+	Local bool
+}
+
+// References returns the declarations of the identifier at a position and
+// the references to it in source.
+func References(ctx context.Context, filepath, source string, pos jpos.Position, nodeCache *NodeCache, libPaths []string) (ReferenceSet, error) {
+	span, _ := opentracing.StartSpanFromContext(ctx, "references")
+	defer span.Finish()
+
+	ri, err := readReferenceIndex(filepath, source, nodeCache, libPaths)
+	if err != nil {
+		return ReferenceSet{}, err
+	}
+
+	decl, ok := ri.at(pos)
+	if !ok {
+		return ReferenceSet{}, nil
+	}
+
+	rs := ReferenceSet{
+		Declarations: ri.declarationsOf(decl),
+		References:   ri.referencesTo(decl),
+		Local:        ri.locals[decl],
+	}
+
+	return rs, nil
+}
+
+// FileReferences returns the references in source to declarations found by
+// References in another file. The declarations in source which override
+// them are returned as well.
+func FileReferences(ctx context.Context, filepath, source string, declarations []jpos.Location, nodeCache *NodeCache, libPaths []string) (ReferenceSet, error) {
+	span, _ := opentracing.StartSpanFromContext(ctx, "fileReferences")
+	defer span.Finish()
+
+	ri, err := readReferenceIndex(filepath, source, nodeCache, libPaths)
+	if err != nil {
+		return ReferenceSet{}, err
+	}
+
+	var rs ReferenceSet
+	seen := make(map[jpos.Location]bool)
+
+	for _, decl := range declarations {
+		for _, loc := range ri.declarationsOf(decl) {
+			if loc.URI() == filepath && !seen[loc] {
+				seen[loc] = true
+				rs.Declarations = append(rs.Declarations, loc)
+			}
+		}
+
+		for _, loc := range ri.referencesTo(decl) {
+			if !seen[loc] {
+				seen[loc] = true
+				rs.References = append(rs.References, loc)
+			}
+		}
+	}
+
+	sortLocations(rs.Declarations)
+	sortLocations(rs.References)
+
+	return rs, nil
+}
+
+func readReferenceIndex(filepath, source string, nodeCache *NodeCache, libPaths []string) (*referenceIndex, error) {
+	node, err := ReadSource(filepath, source, nil)
+	if err != nil {
+		return nil, err
+	}
+
+	sg := scanScope(node, nodeCache)
+
+	return newReferenceIndex(filepath, sg, libPaths, nodeCache), nil
+}
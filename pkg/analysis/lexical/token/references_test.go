@@ -0,0 +1,104 @@
+package token
+
+import (
+	"context"
+	"testing"
+
+	jpos "github.com/tminor/jsonnet-language-server/pkg/util/position"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestReferences(t *testing.T) {
+	file := "testdata/file.jsonnet"
+	lib := "testdata/references.libsonnet"
+
+	source := "local base = import 'references.libsonnet';\n" +
+		"base {\n" +
+		"  name: base.name,\n" +
+		"  labels+: { app: 'app' },\n" +
+		"}"
+
+	cases := []struct {
+		name     string
+		pos      jpos.Position
+		expected ReferenceSet
+	}{
+		{
+			name: "imported field",
+			pos:  jpos.New(3, 14),
+			expected: ReferenceSet{
+				Declarations: []jpos.Location{
+					jpos.NewLocation(file, jpos.NewRangeFromCoords(3, 3, 3, 7)),
+					jpos.NewLocation(lib, jpos.NewRangeFromCoords(2, 3, 2, 7)),
+				},
+				References: []jpos.Location{
+					jpos.NewLocation(file, jpos.NewRangeFromCoords(3, 14, 3, 18)),
+				},
+			},
+		},
+		{
+			name: "merged field",
+			pos:  jpos.New(4, 14),
+			expected: ReferenceSet{
+				Declarations: []jpos.Location{
+					jpos.NewLocation(file, jpos.NewRangeFromCoords(4, 14, 4, 17)),
+					jpos.NewLocation(lib, jpos.NewRangeFromCoords(4, 5, 4, 8)),
+				},
+			},
+		},
+		{
+			name: "local",
+			pos:  jpos.New(3, 9),
+			expected: ReferenceSet{
+				Declarations: []jpos.Location{
+					jpos.NewLocation(file, jpos.NewRangeFromCoords(1, 7, 1, 11)),
+				},
+				References: []jpos.Location{
+					jpos.NewLocation(file, jpos.NewRangeFromCoords(2, 1, 2, 5)),
+					jpos.NewLocation(file, jpos.NewRangeFromCoords(3, 9, 3, 13)),
+				},
+				Local: true,
+			},
+		},
+		{
+			name: "not an identifier",
+			pos:  jpos.New(4, 20),
+		},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			got, err := References(context.Background(), file, source, tc.pos, NewNodeCache(), nil)
+			require.NoError(t, err)
+
+			assert.Equal(t, tc.expected, got)
+		})
+	}
+}
+
+func TestFileReferences(t *testing.T) {
+	file := "testdata/other.jsonnet"
+	lib := "testdata/references.libsonnet"
+
+	source := "local base = import 'references.libsonnet';\n" +
+		"base { name: base.name }"
+
+	declarations := []jpos.Location{
+		jpos.NewLocation(lib, jpos.NewRangeFromCoords(2, 3, 2, 7)),
+	}
+
+	expected := ReferenceSet{
+		Declarations: []jpos.Location{
+			jpos.NewLocation(file, jpos.NewRangeFromCoords(2, 8, 2, 12)),
+		},
+		References: []jpos.Location{
+			jpos.NewLocation(file, jpos.NewRangeFromCoords(2, 19, 2, 23)),
+		},
+	}
+
+	got, err := FileReferences(context.Background(), file, source, declarations, NewNodeCache(), nil)
+	require.NoError(t, err)
+
+	assert.Equal(t, expected, got)
+}
@@ -39,3 +39,21 @@ func resolveIndex(i *ast.Index) []string {
 
 	return path
 }
+
+// isResolvableIndex returns true if an index can be resolved to a path. The
+// index must be rooted at a variable or self.
+func isResolvableIndex(i *ast.Index) bool {
+	var cur ast.Node = i
+	for {
+		switch c := cur.(type) {
+		case *ast.Apply:
+			cur = c.Target
+		case *ast.Index:
+			cur = c.Target
+		case *ast.Self, *ast.Var:
+			return true
+		default:
+			return false
+		}
+	}
+}
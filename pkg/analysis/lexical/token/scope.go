@@ -16,6 +16,9 @@ type ScopeEntry struct {
 	Detail        string
 	Documentation string
 	Node          ast.Node
+	// Depth is the number of scopes enclosing the scope where the entry was
+	// declared. Entries declared in inner scopes have a greater depth.
+	Depth int
 }
 
 // Scope is scope.
@@ -34,6 +37,10 @@ func newScope(nc *NodeCache) *Scope {
 func (sm *Scope) addEvalScope(es *evalScope) {
 	for k, v := range es.store {
 		sm.add(k, v)
+
+		entry := sm.store[string(k)]
+		entry.Depth = es.depths[k]
+		sm.store[string(k)] = entry
 	}
 }
 
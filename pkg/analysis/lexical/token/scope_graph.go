@@ -6,8 +6,8 @@ import (
 
 	"github.com/tminor/jsonnet-language-server/pkg/analysis/lexical/astext"
 	jpos "github.com/tminor/jsonnet-language-server/pkg/util/position"
-	"github.com/davecgh/go-spew/spew"
 	"github.com/google/go-jsonnet/ast"
+	"github.com/pkg/errors"
 )
 
 type locationSet struct {
@@ -74,13 +74,6 @@ func (sr *scopeReference) String() string {
 	}
 }
 
-type objectLookup struct {
-	name   string
-	path   []string
-	r      ast.LocationRange
-	object *ast.DesugaredObject
-}
-
 type objectKey struct {
 	object *ast.DesugaredObject
 	field  string
@@ -91,7 +84,6 @@ type scope struct {
 	declMap   map[ast.Identifier]ast.Node
 	refMap    map[ast.Identifier][]scopeReference
 	parentMap map[ast.Node]ast.Node
-	objectMap map[*ast.DesugaredObject][]objectLookup
 	om        *objectMapper
 	nodeCache *NodeCache
 }
@@ -101,7 +93,6 @@ func newScope2(nodeCache *NodeCache) *scope {
 		idMap:     make(map[ast.Identifier]jpos.Location),
 		declMap:   make(map[ast.Identifier]ast.Node),
 		refMap:    make(map[ast.Identifier][]scopeReference),
-		objectMap: make(map[*ast.DesugaredObject][]objectLookup),
 		om:        &objectMapper{},
 		parentMap: make(map[ast.Node]ast.Node),
 		nodeCache: nodeCache,
@@ -110,47 +101,6 @@ func newScope2(nodeCache *NodeCache) *scope {
 	return s
 }
 
-func (s *scope) refAt(pos jpos.Position) jpos.Locations {
-	var curID *ast.Identifier
-
-	// check if reference is an identifier
-	for id, refs := range s.refMap {
-		for _, ref := range refs {
-			if pos.IsInJsonnetRange(ref.loc) {
-				curID = &id
-			}
-		}
-	}
-
-	var locations jpos.Locations
-	if curID != nil {
-		if _, ok := s.refMap[*curID]; ok {
-			for _, ref := range s.refMap[*curID] {
-				locations.Add(jpos.LocationFromJsonnet(ref.loc))
-
-				switch n := ref.node.(type) {
-				case *ast.Index:
-					path := resolveIndex(n)
-					fmt.Println("resolving index", path[0])
-					if path[0] == "self" {
-						if o, ok := ref.parent.(*ast.DesugaredObject); ok {
-							l, err := s.om.lookup(o, path[1:])
-							if err != nil {
-								break
-							}
-							locations.Add(l)
-						}
-					}
-				default:
-					fmt.Printf("how do I add additional locations for %T\n", n)
-				}
-			}
-		}
-	}
-
-	return locations
-}
-
 func (s *scope) parent(node ast.Node) ast.Node {
 	p := s.parentMap[node]
 	switch p := p.(type) {
@@ -178,68 +128,12 @@ func (s *scope) identify(node ast.Node) (Identity, error) {
 	}
 }
 
-func (s *scope) refersTo(id ast.Identifier, path ...string) []jpos.Location {
-	fmt.Printf("finding what refers to %s at %s\n", id, path)
-	var locations []jpos.Location
-
-	idLoc, ok := s.idMap[id]
-	if !ok {
-		return locations
-	}
-
-	switch n := s.declMap[id].(type) {
-	case *ast.DesugaredObject:
-		if len(path) == 0 {
-			locations = append(locations, idLoc)
-		} else {
-			locations = append(locations, s.findObjectPath(n, path)...)
-		}
-
-		for _, ref := range s.refMap[id] {
-			if slicesEqual(path, ref.path) {
-				locations = append(locations, jpos.LocationFromJsonnet(ref.loc))
-			}
-		}
-	default:
-		locations = append(locations, idLoc)
-		for _, ref := range s.refMap[id] {
-			locations = append(locations, jpos.LocationFromJsonnet(ref.loc))
-		}
-	}
-
-	return locations
-}
-
-func (s *scope) findObjectPath(o *ast.DesugaredObject, path []string) []jpos.Location {
-	var locations []jpos.Location
-
-	fmt.Println("findObjectPath path:", path)
-	for _, ol := range s.objectMap {
-		spew.Dump(ol)
-	}
-
-	lookups, ok := s.objectMap[o]
-	if !ok {
-		return locations
-	}
-	spew.Dump(s.objectMap, path)
-	for _, ol := range lookups {
-		if slicesEqual(ol.path, path) {
-			l := jpos.LocationFromJsonnet(ol.r)
-			locations = append(locations, l)
-		}
-	}
-
-	return locations
-}
-
 func (s *scope) declare(id ast.Identifier, loc ast.LocationRange, node ast.Node) {
 	s.idMap[id] = jpos.LocationFromJsonnet(loc)
 	s.declMap[id] = node
 }
 
 func (s *scope) reference(id ast.Identifier, parent, node ast.Node, path ...string) {
-	fmt.Printf("creating reference %s%s\n", id, path)
 	var loc ast.LocationRange
 	switch node := node.(type) {
 	case *ast.Index:
@@ -264,8 +158,6 @@ func (s *scope) reference(id ast.Identifier, parent, node ast.Node, path ...stri
 		loc:    loc,
 	}
 
-	fmt.Printf("created sr for %T path%s at %v\n", node, path, loc.String())
-
 	if _, ok := s.refMap[id]; !ok {
 		s.refMap[id] = make([]scopeReference, 0)
 	}
@@ -274,22 +166,25 @@ func (s *scope) reference(id ast.Identifier, parent, node ast.Node, path ...stri
 }
 
 func (s *scope) indexObject(cur *ast.DesugaredObject, name string) {
-	if err := s.om.add(cur, name); err != nil {
-		panic(fmt.Sprintf("add field: %v", err))
-	}
+	// objects created while desugaring do not have field locations, so
+	// there is nothing to index.
+	_ = s.om.add(cur, name)
 }
 
 func (s *scope) Clone() *scope {
 	clone := &scope{
-		idMap:     s.idMap,
+		idMap:     make(map[ast.Identifier]jpos.Location),
 		declMap:   make(map[ast.Identifier]ast.Node),
 		refMap:    s.refMap,
-		objectMap: s.objectMap,
 		om:        s.om,
 		parentMap: s.parentMap,
 		nodeCache: s.nodeCache,
 	}
 
+	for k, v := range s.idMap {
+		clone.idMap[k] = v
+	}
+
 	for k, v := range s.declMap {
 		clone.declMap[k] = v
 	}
@@ -299,22 +194,147 @@ func (s *scope) Clone() *scope {
 
 type scopeGraph struct {
 	idScopes      map[ast.Node]*scope
+	parents       map[ast.Node]ast.Node
 	root          ast.Node
 	currentObject *ast.DesugaredObject
+	// declarations are the identifiers declared by locals and function
+	// parameters by where they begin.
+	declarations map[ast.Location]ast.LocationRange
+	// references are the variables which refer to each declaration.
+	references map[ast.Location][]ast.LocationRange
 }
 
 func scanScope(node ast.Node, nc *NodeCache) *scopeGraph {
 	s := newScope2(nc)
 
 	sg := &scopeGraph{
-		idScopes: make(map[ast.Node]*scope),
-		root:     node,
+		idScopes:     make(map[ast.Node]*scope),
+		parents:      s.parentMap,
+		root:         node,
+		declarations: make(map[ast.Location]ast.LocationRange),
+		references:   make(map[ast.Location][]ast.LocationRange),
 	}
 	sg.visit(nil, node, s)
 
 	return sg
 }
 
+// declareID records where an identifier is declared. Identifiers created
+// while desugaring don't have a location and are ignored.
+func (sg *scopeGraph) declareID(loc ast.LocationRange) {
+	if loc.Begin.Line == 0 {
+		return
+	}
+
+	sg.declarations[loc.Begin] = loc
+}
+
+// referenceID connects a variable to the identifier it refers to.
+func (sg *scopeGraph) referenceID(v *ast.Var, s *scope) {
+	if v.Loc().Begin.Line == 0 {
+		return
+	}
+
+	loc, ok := s.idMap[v.Id]
+	if !ok {
+		return
+	}
+
+	begin := loc.ToJsonnet().Begin
+	if _, ok := sg.declarations[begin]; !ok {
+		return
+	}
+
+	sg.references[begin] = append(sg.references[begin], *v.Loc())
+}
+
+// parentOf returns the parent of a node.
+func (sg *scopeGraph) parentOf(n ast.Node) ast.Node {
+	if sg == nil {
+		return nil
+	}
+
+	return sg.parents[n]
+}
+
+// declaration returns the node bound to a variable.
+func (sg *scopeGraph) declaration(v *ast.Var) (ast.Node, error) {
+	if sg == nil {
+		return nil, errors.Errorf("variable %q has no scope", v.Id)
+	}
+
+	s, ok := sg.idScopes[v]
+	if !ok {
+		return nil, errors.Errorf("variable %q has no scope", v.Id)
+	}
+
+	decl, ok := s.declMap[v.Id]
+	if !ok || decl == nil {
+		return nil, errors.Errorf("variable %q is not bound to a value", v.Id)
+	}
+
+	return decl, nil
+}
+
+// enclosingObject returns the object a node is defined in.
+func (sg *scopeGraph) enclosingObject(n ast.Node) (*ast.DesugaredObject, error) {
+	for cur := sg.parentOf(n); cur != nil; cur = sg.parentOf(cur) {
+		if o, ok := cur.(*ast.DesugaredObject); ok {
+			return o, nil
+		}
+	}
+
+	return nil, errors.Errorf("%T is not in an object", n)
+}
+
+// objectAt returns the object which begins at a location.
+func (sg *scopeGraph) objectAt(loc ast.Location) *ast.DesugaredObject {
+	for n := range sg.parents {
+		o, ok := n.(*ast.DesugaredObject)
+		if ok && o.Loc().Begin == loc {
+			return o
+		}
+	}
+
+	return nil
+}
+
+// partialIndexAt returns the partial index for a dot at a location.
+func (sg *scopeGraph) partialIndexAt(dot ast.Location) *astext.PartialIndex {
+	var found *astext.PartialIndex
+	for n := range sg.parents {
+		pi, ok := n.(*astext.PartialIndex)
+		if !ok || pi.Target == nil {
+			continue
+		}
+
+		end := pi.Target.Loc().End
+		if locationBefore(dot, end) {
+			continue
+		}
+
+		if found == nil || locationBefore(found.Target.Loc().End, end) {
+			found = pi
+		}
+	}
+
+	return found
+}
+
+// composition returns the outermost `+` expression an object is a part of.
+// If the object isn't composed, the object is returned.
+func (sg *scopeGraph) composition(o *ast.DesugaredObject) ast.Node {
+	var cur ast.Node = o
+	for {
+		b, ok := sg.parentOf(cur).(*ast.Binary)
+		if !ok || b.Op != ast.BopPlus {
+			return cur
+		}
+
+		cur = b
+	}
+}
+
 func (sg *scopeGraph) at(pos jpos.Position) (ast.Node, *scope, error) {
 	n, err := locateNode(sg.root, pos)
 	if err != nil {
@@ -384,9 +404,11 @@ func (sg *scopeGraph) visit(parent, n ast.Node, parentScope *scope) {
 
 		for _, param := range n.Parameters.Required {
 			currentScope.declare(param, n.Parameters.RequiredLocs[param], nil)
+			sg.declareID(n.Parameters.RequiredLocs[param])
 		}
 		for _, param := range n.Parameters.Optional {
 			currentScope.declare(param.Name, param.Loc, param.DefaultArg)
+			sg.declareID(identifierRange(param.Loc.Begin, string(param.Name)))
 		}
 		for _, param := range n.Parameters.Optional {
 			sg.visit(n, param.DefaultArg, currentScope)
@@ -397,14 +419,16 @@ func (sg *scopeGraph) visit(parent, n ast.Node, parentScope *scope) {
 	case *ast.InSuper:
 		sg.visit(n, n.Index, currentScope)
 	case *ast.Index:
-		path := resolveIndex(n)
+		if isResolvableIndex(n) {
+			path := resolveIndex(n)
 
-		refPath := make([]string, 0)
-		if len(path) > 1 {
-			refPath = path[1:]
-		}
+			refPath := make([]string, 0)
+			if len(path) > 1 {
+				refPath = path[1:]
+			}
 
-		currentScope.reference(ast.Identifier(path[0]), sg.currentObject, n, refPath...)
+			currentScope.reference(ast.Identifier(path[0]), sg.currentObject, n, refPath...)
+		}
 
 		sg.visit(n, n.Target, currentScope)
 		sg.visit(n, n.Index, currentScope)
@@ -415,8 +439,8 @@ func (sg *scopeGraph) visit(parent, n ast.Node, parentScope *scope) {
 	case *ast.Local:
 		currentScope = currentScope.Clone()
 		for _, bind := range n.Binds {
-
 			currentScope.declare(bind.Variable, bind.VarLoc, bind.Body)
+			sg.declareID(bind.VarLoc)
 		}
 
 		for _, bind := range n.Binds {
@@ -424,8 +448,10 @@ func (sg *scopeGraph) visit(parent, n ast.Node, parentScope *scope) {
 		}
 
 		sg.visit(n, n.Body, currentScope)
-	case *astext.Partial, *astext.PartialIndex:
+	case *astext.Partial:
 		// nothing to do
+	case *astext.PartialIndex:
+		sg.visit(n, n.Target, currentScope)
 	case *ast.Self:
 	case *ast.SuperIndex:
 		sg.visit(n, n.Index, currentScope)
@@ -433,6 +459,7 @@ func (sg *scopeGraph) visit(parent, n ast.Node, parentScope *scope) {
 		sg.visit(n, n.Expr, currentScope)
 	case *ast.Var:
 		currentScope.reference(n.Id, parent, n)
+		sg.referenceID(n, currentScope)
 	default:
 		panic(fmt.Sprintf("unexpected node %T", n))
 	}
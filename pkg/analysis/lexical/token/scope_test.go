@@ -63,6 +63,25 @@ func TestScope(t *testing.T) {
 	}
 }
 
+func TestScope_depth(t *testing.T) {
+	src := "local a=1; local f(b)=\n  local c=b; c;\nf(a)"
+
+	nc := NewNodeCache()
+	sm, err := LocationScope("file.jsonnet", src, jlspos.New(2, 14), nc)
+	require.NoError(t, err)
+
+	depths := make(map[string]int)
+	for _, k := range sm.Keys() {
+		e, err := sm.Get(k)
+		require.NoError(t, err)
+		depths[k] = e.Depth
+	}
+
+	assert.True(t, depths["std"] < depths["a"])
+	assert.True(t, depths["a"] < depths["b"])
+	assert.True(t, depths["b"] < depths["c"])
+}
+
 func TestScopeMap(t *testing.T) {
 	nc := NewNodeCache()
 	sm := newScope(nc)
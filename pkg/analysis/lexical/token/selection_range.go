@@ -0,0 +1,286 @@
+package token
+
+import (
+	"strings"
+
+	"github.com/tminor/jsonnet-language-server/pkg/analysis/lexical/astext"
+	jpos "github.com/tminor/jsonnet-language-server/pkg/util/position"
+	"github.com/google/go-jsonnet/ast"
+)
+
+// SelectionRanges returns the ranges enclosing each position, from the
+// smallest range to the whole file. Ranges are found in the AST before it is
+// desugared, so they follow the source, e.g. a field name, the field and the
+// object containing it.
+func SelectionRanges(filename, source string, positions []jpos.Position) ([][]jpos.Range, error) {
+	node, err := Parse(filename, source, nil)
+	if err != nil {
+		return nil, err
+	}
+
+	file := fileRange(source)
+
+	var out [][]jpos.Range
+	for _, pos := range positions {
+		sv := &selectionVisitor{
+			pos:    pos,
+			ranges: []jpos.Range{file},
+		}
+		sv.visit(node)
+
+		ranges := make([]jpos.Range, 0, len(sv.ranges))
+		for i := len(sv.ranges) - 1; i >= 0; i-- {
+			ranges = append(ranges, sv.ranges[i])
+		}
+
+		out = append(out, ranges)
+	}
+
+	return out, nil
+}
+
+// fileRange is the range of the whole source.
+func fileRange(source string) jpos.Range {
+	lines := strings.Split(source, "\n")
+	last := lines[len(lines)-1]
+
+	return jpos.NewRange(jpos.New(1, 1), jpos.New(len(lines), len(last)+1))
+}
+
+// selectionVisitor collects the ranges enclosing a position. Ranges are
+// collected from the outermost range.
+type selectionVisitor struct {
+	pos    jpos.Position
+	ranges []jpos.Range
+}
+
+// add adds a range if it encloses the position. It returns false if the
+// range doesn't enclose the position.
+func (sv *selectionVisitor) add(r ast.LocationRange) bool {
+	if r.Begin.Line == 0 || !sv.pos.IsInJsonnetRange(r) {
+		return false
+	}
+
+	jr := jpos.FromJsonnetRange(r)
+	if n := len(sv.ranges); n > 0 && sv.ranges[n-1] == jr {
+		return true
+	}
+
+	sv.ranges = append(sv.ranges, jr)
+
+	return true
+}
+
+// addIdentifier adds the range of an identifier beginning at a location.
+func (sv *selectionVisitor) addIdentifier(begin ast.Location, name string) bool {
+	return sv.add(identifierRange(begin, name))
+}
+
+// addIndexID adds the range of the identifier at the end of an index, e.g.
+// `b` in `a.b`.
+func (sv *selectionVisitor) addIndexID(loc ast.LocationRange, id *ast.Identifier) bool {
+	if id == nil {
+		return false
+	}
+
+	return sv.add(indexNameRange(loc, string(*id)))
+}
+
+// visit visits a node if it encloses the position. It returns false if the
+// node doesn't enclose the position.
+func (sv *selectionVisitor) visit(n ast.Node) bool {
+	if n == nil || !sv.add(*n.Loc()) {
+		return false
+	}
+
+	sv.visitChildren(n)
+
+	return true
+}
+
+// visitFirst visits nodes until one encloses the position.
+func (sv *selectionVisitor) visitFirst(nodes ...ast.Node) bool {
+	for _, n := range nodes {
+		if sv.visit(n) {
+			return true
+		}
+	}
+
+	return false
+}
+
+// nolint: gocyclo
+func (sv *selectionVisitor) visitChildren(n ast.Node) {
+	switch n := n.(type) {
+	case *ast.Apply:
+		if sv.visitFirst(append([]ast.Node{n.Target}, n.Arguments.Positional...)...) {
+			return
+		}
+		for _, arg := range n.Arguments.Named {
+			if sv.add(arg.Loc) {
+				if !sv.addIdentifier(arg.Loc.Begin, string(arg.Name)) {
+					sv.visit(arg.Arg)
+				}
+				return
+			}
+		}
+	case *ast.ApplyBrace:
+		sv.visitFirst(n.Left, n.Right)
+	case *ast.Array:
+		sv.visitFirst(n.Elements...)
+	case *ast.ArrayComp:
+		if !sv.visit(n.Body) {
+			sv.visitForSpec(&n.Spec)
+		}
+	case *ast.Assert:
+		sv.visitFirst(n.Cond, n.Message, n.Rest)
+	case *ast.Binary:
+		sv.visitFirst(n.Left, n.Right)
+	case *ast.Conditional:
+		sv.visitFirst(n.Cond, n.BranchTrue, n.BranchFalse)
+	case *ast.Error:
+		sv.visit(n.Expr)
+	case *ast.Function:
+		if !sv.visitParameters(&n.Parameters) {
+			sv.visit(n.Body)
+		}
+	case *ast.Index:
+		if !sv.visitFirst(n.Target, n.Index) {
+			sv.addIndexID(*n.Loc(), n.Id)
+		}
+	case *ast.InSuper:
+		sv.visit(n.Index)
+	case *ast.Local:
+		for _, bind := range n.Binds {
+			if sv.visitBind(bind) {
+				return
+			}
+		}
+		sv.visit(n.Body)
+	case *ast.Object:
+		sv.visitFields(n.Fields, n.FieldLocs)
+	case *ast.ObjectComp:
+		if !sv.visitFields(n.Fields, nil) {
+			sv.visitForSpec(&n.Spec)
+		}
+	case *ast.Parens:
+		sv.visit(n.Inner)
+	case *ast.Slice:
+		sv.visitFirst(n.Target, n.BeginIndex, n.EndIndex, n.Step)
+	case *ast.SuperIndex:
+		if !sv.visit(n.Index) {
+			sv.addIndexID(*n.Loc(), n.Id)
+		}
+	case *ast.Unary:
+		sv.visit(n.Expr)
+	case *astext.PartialIndex:
+		sv.visit(n.Target)
+	}
+}
+
+// visitBind visits a local bind. The bind encloses its name and its value.
+func (sv *selectionVisitor) visitBind(bind ast.LocalBind) bool {
+	if bind.VarLoc.Begin.Line == 0 || bind.Body == nil {
+		return false
+	}
+
+	r := ast.LocationRange{Begin: bind.VarLoc.Begin, End: bind.Body.Loc().End}
+	if !sv.add(r) {
+		return false
+	}
+
+	if sv.add(bind.VarLoc) {
+		return true
+	}
+
+	if bind.Fun != nil && sv.visitParameters(&bind.Fun.Parameters) {
+		return true
+	}
+
+	sv.visit(bind.Body)
+
+	return true
+}
+
+// visitFields visits object fields. A field encloses its name and its
+// value.
+func (sv *selectionVisitor) visitFields(fields ast.ObjectFields, fieldLocs map[interface{}]ast.LocationRange) bool {
+	for _, field := range fields {
+		var name ast.LocationRange
+		switch field.Kind {
+		case ast.ObjectFieldID:
+			if field.Id != nil {
+				name = fieldLocs[*field.Id]
+			}
+		case ast.ObjectFieldExpr, ast.ObjectFieldStr:
+			name = *field.Expr1.Loc()
+		}
+
+		if name.Begin.Line == 0 || field.Expr2 == nil {
+			if sv.visitFirst(field.Expr2, field.Expr3) {
+				return true
+			}
+			continue
+		}
+
+		if !sv.add(ast.LocationRange{Begin: name.Begin, End: field.Expr2.Loc().End}) {
+			continue
+		}
+
+		if field.Kind == ast.ObjectFieldID {
+			if sv.add(name) {
+				return true
+			}
+		} else if sv.visit(field.Expr1) {
+			return true
+		}
+
+		if field.Params != nil && sv.visitParameters(field.Params) {
+			return true
+		}
+
+		sv.visit(field.Expr2)
+
+		return true
+	}
+
+	return false
+}
+
+// visitParameters visits function parameters and their default values.
+func (sv *selectionVisitor) visitParameters(params *ast.Parameters) bool {
+	for _, param := range params.Required {
+		if sv.add(params.RequiredLocs[param]) {
+			return true
+		}
+	}
+
+	for _, param := range params.Optional {
+		if sv.add(param.Loc) {
+			if !sv.addIdentifier(param.Loc.Begin, string(param.Name)) {
+				sv.visit(param.DefaultArg)
+			}
+			return true
+		}
+	}
+
+	return false
+}
+
+// visitForSpec visits the expressions in a comprehension's for and if
+// clauses.
+func (sv *selectionVisitor) visitForSpec(spec *ast.ForSpec) bool {
+	for ; spec != nil; spec = spec.Outer {
+		if sv.visit(spec.Expr) {
+			return true
+		}
+
+		for _, cond := range spec.Conditions {
+			if sv.visit(cond.Expr) {
+				return true
+			}
+		}
+	}
+
+	return false
+}
@@ -0,0 +1,61 @@
+package token
+
+import (
+	"testing"
+
+	jpos "github.com/tminor/jsonnet-language-server/pkg/util/position"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestSelectionRanges(t *testing.T) {
+	cases := []struct {
+		name     string
+		source   string
+		pos      jpos.Position
+		expected []jpos.Range
+	}{
+		{
+			name:   "field in object",
+			source: "local a = {\n  b: c.d,\n};\na",
+			pos:    jpos.New(2, 8),
+			expected: []jpos.Range{
+				jpos.NewRangeFromCoords(2, 8, 2, 9),
+				jpos.NewRangeFromCoords(2, 6, 2, 9),
+				jpos.NewRangeFromCoords(2, 3, 2, 9),
+				jpos.NewRangeFromCoords(1, 11, 3, 2),
+				jpos.NewRangeFromCoords(1, 7, 3, 2),
+				jpos.NewRangeFromCoords(1, 1, 4, 2),
+			},
+		},
+		{
+			name:   "optional parameter",
+			source: "local f(x, y=1) = x;\nf(1)",
+			pos:    jpos.New(1, 14),
+			expected: []jpos.Range{
+				jpos.NewRangeFromCoords(1, 14, 1, 15),
+				jpos.NewRangeFromCoords(1, 12, 1, 15),
+				jpos.NewRangeFromCoords(1, 7, 1, 20),
+				jpos.NewRangeFromCoords(1, 1, 2, 5),
+			},
+		},
+		{
+			name:   "outside of nodes",
+			source: "{}\n",
+			pos:    jpos.New(2, 1),
+			expected: []jpos.Range{
+				jpos.NewRangeFromCoords(1, 1, 2, 1),
+			},
+		},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			got, err := SelectionRanges("file.jsonnet", tc.source, []jpos.Position{tc.pos})
+			require.NoError(t, err)
+			require.Len(t, got, 1)
+
+			assert.Equal(t, tc.expected, got[0])
+		})
+	}
+}
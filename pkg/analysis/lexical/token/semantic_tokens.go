@@ -0,0 +1,368 @@
+package token
+
+import (
+	"strings"
+
+	jpos "github.com/tminor/jsonnet-language-server/pkg/util/position"
+	"github.com/google/go-jsonnet/ast"
+)
+
+// SemanticTokenType is the type of a semantic token. It is an index into
+// SemanticTokenTypes.
+type SemanticTokenType int
+
+const (
+	// SemanticVariable is a local variable.
+	SemanticVariable SemanticTokenType = iota
+	// SemanticParameter is a function parameter.
+	SemanticParameter
+	// SemanticProperty is an object field.
+	SemanticProperty
+	// SemanticMethod is an object field which is a function.
+	SemanticMethod
+	// SemanticFunction is a local variable which is a function.
+	SemanticFunction
+	// SemanticKeyword is a keyword.
+	SemanticKeyword
+	// SemanticString is a string literal.
+	SemanticString
+	// SemanticNumber is a number literal.
+	SemanticNumber
+	// SemanticOperator is an operator.
+	SemanticOperator
+)
+
+// SemanticTokenModifiers are modifiers of a semantic token. Each bit is an
+// index into SemanticTokenModifierNames.
+type SemanticTokenModifiers int
+
+const (
+	// SemanticDeclaration is where a variable, parameter or field is
+	// declared.
+	SemanticDeclaration SemanticTokenModifiers = 1 << iota
+	// SemanticReadonly is a variable which can't be changed. All Jsonnet
+	// variables are readonly.
+	SemanticReadonly
+	// SemanticDeprecated is a declaration which is documented with a
+	// `@deprecated` tag.
+	SemanticDeprecated
+	// SemanticDefaultLibrary is std or a member of std.
+	SemanticDefaultLibrary
+	// SemanticHidden is a hidden field.
+	SemanticHidden
+)
+
+var (
+	// SemanticTokenTypes are the names of the semantic token types.
+	SemanticTokenTypes = []string{
+		"variable",
+		"parameter",
+		"property",
+		"method",
+		"function",
+		"keyword",
+		"string",
+		"number",
+		"operator",
+	}
+
+	// SemanticTokenModifierNames are the names of the semantic token
+	// modifiers.
+	SemanticTokenModifierNames = []string{
+		"declaration",
+		"readonly",
+		"deprecated",
+		"defaultLibrary",
+		"hidden",
+	}
+)
+
+const (
+	// deprecatedTag marks a declaration as deprecated in its doc comment.
+	deprecatedTag = "@deprecated"
+)
+
+// SemanticToken is a token classified using the scope of the document.
+type SemanticToken struct {
+	Position  jpos.Position
+	Length    int
+	Type      SemanticTokenType
+	Modifiers SemanticTokenModifiers
+}
+
+// SemanticTokens classifies the tokens in source. Identifiers are classified
+// by what they refer to. Tokens which span multiple lines are not included.
+func SemanticTokens(filename, source string, nodeCache *NodeCache, libPaths []string) ([]SemanticToken, error) {
+	tokens, err := Lex(filename, source)
+	if err != nil {
+		return nil, err
+	}
+
+	node, err := ReadSource(filename, source, nil)
+	if err != nil {
+		return nil, err
+	}
+
+	sc := newSemanticClassifier(filename, source, tokens, node, nodeCache, libPaths)
+	sc.classify()
+
+	var out []SemanticToken
+	for i := range tokens {
+		t := &tokens[i]
+		if t.Loc.Begin.Line != t.Loc.End.Line {
+			continue
+		}
+
+		st := SemanticToken{
+			Position: jpos.FromJsonnetLocation(t.Loc.Begin),
+			Length:   t.Loc.End.Column - t.Loc.Begin.Column,
+		}
+
+		switch {
+		case t.Kind == TokenIdentifier:
+			c, ok := sc.identifiers[t.Loc.Begin]
+			if !ok {
+				c = unresolvedClass(tokens, i)
+			}
+			st.Type = c.kind
+			st.Modifiers = c.modifiers
+		case t.Kind == TokenDollar, t.Kind >= TokenAssert && t.Kind <= TokenTrue:
+			st.Type = SemanticKeyword
+		case t.Kind == TokenNumber:
+			st.Type = SemanticNumber
+		case t.Kind == TokenOperator:
+			st.Type = SemanticOperator
+		case t.Kind >= TokenStringBlock && t.Kind <= TokenVerbatimStringSingle:
+			st.Type = SemanticString
+		default:
+			continue
+		}
+
+		out = append(out, st)
+	}
+
+	return out, nil
+}
+
+type semanticClass struct {
+	kind      SemanticTokenType
+	modifiers SemanticTokenModifiers
+}
+
+// semanticClassifier classifies identifiers by the location they begin at.
+// Declarations are classified first, so references can be classified by
+// their declaration.
+type semanticClassifier struct {
+	filename    string
+	source      string
+	tokens      Tokens
+	graph       *scopeGraph
+	resolver    *objectResolver
+	identifiers map[ast.Location]semanticClass
+	declared    map[ast.Location]semanticClass
+	deprecated  map[jpos.Location]bool
+}
+
+func newSemanticClassifier(filename, source string, tokens Tokens, node ast.Node, nodeCache *NodeCache, libPaths []string) *semanticClassifier {
+	sg := scanScope(node, nodeCache)
+
+	return &semanticClassifier{
+		filename:    filename,
+		source:      source,
+		tokens:      tokens,
+		graph:       sg,
+		resolver:    newObjectResolver(filename, sg, libPaths, nodeCache),
+		identifiers: make(map[ast.Location]semanticClass),
+		declared:    make(map[ast.Location]semanticClass),
+		deprecated:  make(map[jpos.Location]bool),
+	}
+}
+
+func (sc *semanticClassifier) classify() {
+	for n := range sc.graph.parents {
+		switch n := n.(type) {
+		case *ast.Local:
+			for _, bind := range n.Binds {
+				kind := SemanticVariable
+				if _, ok := bind.Body.(*ast.Function); ok {
+					kind = SemanticFunction
+				}
+				sc.declare(bind.VarLoc.Begin, kind, SemanticReadonly)
+			}
+		case *ast.Function:
+			for _, param := range n.Parameters.Required {
+				sc.declare(n.Parameters.RequiredLocs[param].Begin, SemanticParameter, SemanticReadonly)
+			}
+			for _, param := range n.Parameters.Optional {
+				sc.declare(param.Loc.Begin, SemanticParameter, SemanticReadonly)
+			}
+		case *ast.DesugaredObject:
+			for _, field := range n.Fields {
+				name, err := fieldName(field)
+				if err != nil {
+					continue
+				}
+
+				loc, ok := n.FieldLocs[name]
+				if !ok {
+					continue
+				}
+
+				f := Field{Hide: field.Hide, Node: fieldBody(field)}
+				sc.declare(loc.Begin, fieldKind(f), fieldModifiers(f))
+			}
+		}
+	}
+
+	for n := range sc.graph.parents {
+		switch n := n.(type) {
+		case *ast.Var:
+			sc.classifyVar(n)
+		case *ast.Index:
+			sc.classifyIndex(n)
+		}
+	}
+}
+
+// declare classifies a declaration. Declarations without a location were
+// created while desugaring and are ignored.
+func (sc *semanticClassifier) declare(loc ast.Location, kind SemanticTokenType, modifiers SemanticTokenModifiers) {
+	if loc.Line == 0 {
+		return
+	}
+
+	if sc.isDeprecated(jpos.NewLocation(sc.filename, jpos.FromJsonnetRange(ast.LocationRange{Begin: loc}))) {
+		modifiers |= SemanticDeprecated
+	}
+
+	c := semanticClass{kind: kind, modifiers: modifiers}
+	sc.declared[loc] = c
+	c.modifiers |= SemanticDeclaration
+	sc.identifiers[loc] = c
+}
+
+func (sc *semanticClassifier) classifyVar(v *ast.Var) {
+	begin := v.Loc().Begin
+	if begin.Line == 0 || v.Id == ast.Identifier(keywordDollar) {
+		return
+	}
+
+	if v.Id == "std" {
+		sc.identifiers[begin] = semanticClass{
+			kind:      SemanticVariable,
+			modifiers: SemanticReadonly | SemanticDefaultLibrary,
+		}
+		return
+	}
+
+	s, ok := sc.graph.idScopes[v]
+	if !ok {
+		return
+	}
+
+	loc, ok := s.idMap[v.Id]
+	if !ok {
+		return
+	}
+
+	if c, ok := sc.declared[loc.ToJsonnet().Begin]; ok {
+		sc.identifiers[begin] = c
+	}
+}
+
+// classifyIndex classifies the field name in an index. The field's
+// declaration is found with the object resolver, so fields declared in
+// other files are classified as well.
+func (sc *semanticClassifier) classifyIndex(idx *ast.Index) {
+	name, ok := idx.Index.(*ast.LiteralString)
+	if !ok {
+		return
+	}
+
+	end := idx.Loc().End
+	begin := ast.Location{Line: end.Line, Column: end.Column - len(name.Value)}
+	if end.Line == 0 || begin.Column < 1 {
+		return
+	}
+
+	if v, ok := idx.Target.(*ast.Var); ok && v.Id == "std" {
+		sc.identifiers[begin] = semanticClass{
+			kind:      SemanticFunction,
+			modifiers: SemanticReadonly | SemanticDefaultLibrary,
+		}
+		return
+	}
+
+	c := semanticClass{kind: SemanticProperty}
+	defer func() { sc.identifiers[begin] = c }()
+
+	layers, err := sc.resolver.layers(sc.graph, idx.Target)
+	if err != nil {
+		return
+	}
+
+	field, ok := lookupField(sc.resolver.fields(layers), name.Value)
+	if !ok {
+		return
+	}
+
+	c = semanticClass{kind: fieldKind(field), modifiers: fieldModifiers(field)}
+
+	if field.Location.URI() != "" && sc.isDeprecated(field.Location) {
+		c.modifiers |= SemanticDeprecated
+	}
+}
+
+// isDeprecated returns true if the declaration at a location is documented
+// as deprecated. Results are cached since fields in other files are read
+// from disk.
+func (sc *semanticClassifier) isDeprecated(loc jpos.Location) bool {
+	if deprecated, ok := sc.deprecated[loc]; ok {
+		return deprecated
+	}
+
+	begin := loc.ToJsonnet().Begin
+
+	var doc string
+	if loc.URI() == sc.filename {
+		for i := range sc.tokens {
+			if sc.tokens[i].Loc.Begin == begin {
+				doc = tokenDoc(sc.tokens, i)
+				break
+			}
+		}
+	} else if source, err := sourceFor(loc.URI(), sc.filename, sc.source); err == nil {
+		doc, _ = DocComment(loc.URI(), source, begin)
+	}
+
+	deprecated := strings.Contains(doc, deprecatedTag)
+	sc.deprecated[loc] = deprecated
+
+	return deprecated
+}
+
+// unresolvedClass classifies an identifier which wasn't found in the scope.
+// Identifiers after a dot are fields, e.g. `super.a`.
+func unresolvedClass(tokens Tokens, i int) semanticClass {
+	if i > 0 && tokens[i-1].Kind == TokenDot {
+		return semanticClass{kind: SemanticProperty}
+	}
+
+	return semanticClass{kind: SemanticVariable, modifiers: SemanticReadonly}
+}
+
+func fieldKind(f Field) SemanticTokenType {
+	if f.IsFunction() {
+		return SemanticMethod
+	}
+
+	return SemanticProperty
+}
+
+func fieldModifiers(f Field) SemanticTokenModifiers {
+	if f.IsHidden() {
+		return SemanticHidden
+	}
+
+	return 0
+}
@@ -0,0 +1,124 @@
+package token
+
+import (
+	"testing"
+
+	jpos "github.com/tminor/jsonnet-language-server/pkg/util/position"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestSemanticTokens(t *testing.T) {
+	source := "// @deprecated\nlocal a = 1;\nlocal f(x) = x + std.length([]);\n{b:: a, c: f(a), d: self.b}"
+
+	cases := []struct {
+		name      string
+		pos       jpos.Position
+		length    int
+		kind      SemanticTokenType
+		modifiers SemanticTokenModifiers
+	}{
+		{
+			name:      "deprecated local declaration",
+			pos:       jpos.New(2, 7),
+			length:    1,
+			kind:      SemanticVariable,
+			modifiers: SemanticDeclaration | SemanticReadonly | SemanticDeprecated,
+		},
+		{
+			name:   "number",
+			pos:    jpos.New(2, 11),
+			length: 1,
+			kind:   SemanticNumber,
+		},
+		{
+			name:      "function declaration",
+			pos:       jpos.New(3, 7),
+			length:    1,
+			kind:      SemanticFunction,
+			modifiers: SemanticDeclaration | SemanticReadonly,
+		},
+		{
+			name:      "parameter declaration",
+			pos:       jpos.New(3, 9),
+			length:    1,
+			kind:      SemanticParameter,
+			modifiers: SemanticDeclaration | SemanticReadonly,
+		},
+		{
+			name:      "parameter",
+			pos:       jpos.New(3, 14),
+			length:    1,
+			kind:      SemanticParameter,
+			modifiers: SemanticReadonly,
+		},
+		{
+			name:      "std",
+			pos:       jpos.New(3, 18),
+			length:    3,
+			kind:      SemanticVariable,
+			modifiers: SemanticReadonly | SemanticDefaultLibrary,
+		},
+		{
+			name:      "std member",
+			pos:       jpos.New(3, 22),
+			length:    6,
+			kind:      SemanticFunction,
+			modifiers: SemanticReadonly | SemanticDefaultLibrary,
+		},
+		{
+			name:      "hidden field declaration",
+			pos:       jpos.New(4, 2),
+			length:    1,
+			kind:      SemanticProperty,
+			modifiers: SemanticDeclaration | SemanticHidden,
+		},
+		{
+			name:      "deprecated local",
+			pos:       jpos.New(4, 6),
+			length:    1,
+			kind:      SemanticVariable,
+			modifiers: SemanticReadonly | SemanticDeprecated,
+		},
+		{
+			name:      "function",
+			pos:       jpos.New(4, 12),
+			length:    1,
+			kind:      SemanticFunction,
+			modifiers: SemanticReadonly,
+		},
+		{
+			name:   "keyword",
+			pos:    jpos.New(4, 21),
+			length: 4,
+			kind:   SemanticKeyword,
+		},
+		{
+			name:      "hidden field",
+			pos:       jpos.New(4, 26),
+			length:    1,
+			kind:      SemanticProperty,
+			modifiers: SemanticHidden,
+		},
+	}
+
+	tokens, err := SemanticTokens("file.jsonnet", source, NewNodeCache(), nil)
+	require.NoError(t, err)
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			var found *SemanticToken
+			for i := range tokens {
+				if tokens[i].Position == tc.pos {
+					found = &tokens[i]
+					break
+				}
+			}
+
+			require.NotNil(t, found)
+			assert.Equal(t, tc.length, found.Length)
+			assert.Equal(t, tc.kind, found.Type)
+			assert.Equal(t, tc.modifiers, found.Modifiers)
+		})
+	}
+}
@@ -65,48 +65,54 @@ func SignatureHelper(source string, pos jpos.Position, nodeCache *NodeCache) (*S
 		return nil, errors.New("node was not a function")
 	}
 
-	var label bytes.Buffer
-	fmt.Fprintf(&label, "%s(", name)
+	list, params, err := parameterList(funNode)
+	if err != nil {
+		return nil, err
+	}
+
+	sr := &SignatureResponse{
+		Label:      fmt.Sprintf("%s(%s)", name, list),
+		Parameters: params,
+	}
+
+	return sr, nil
+}
 
+// parameterList describes the parameters of a function, e.g. `x, y=1`. It
+// also returns the parameter names.
+func parameterList(fn *ast.Function) (string, []string, error) {
+	var list bytes.Buffer
 	var params []string
 
-	required := funNode.Parameters.Required
+	required := fn.Parameters.Required
 	for i, p := range required {
-		label.WriteString(string(p))
+		list.WriteString(string(p))
 		if i < len(required)-1 {
-			label.WriteString(", ")
+			list.WriteString(", ")
 		}
 
 		params = append(params, string(p))
 	}
 
-	optional := funNode.Parameters.Optional
+	optional := fn.Parameters.Optional
 	if len(optional) > 0 && len(required) > 0 {
-		label.WriteString(", ")
+		list.WriteString(", ")
 	}
 
 	for i, p := range optional {
 		var nodeBuf bytes.Buffer
-		if err = printer.Fprint(&nodeBuf, p.DefaultArg); err != nil {
-			return nil, err
+		if err := printer.Fprint(&nodeBuf, p.DefaultArg); err != nil {
+			return "", nil, err
 		}
 
-		fmt.Fprintf(&label, "%s=%s", string(p.Name), nodeBuf.String())
+		fmt.Fprintf(&list, "%s=%s", string(p.Name), nodeBuf.String())
 
 		if i < len(optional)-1 {
-			label.WriteString(", ")
+			list.WriteString(", ")
 		}
 
 		params = append(params, string(p.Name))
-
 	}
 
-	label.WriteString(")")
-
-	sr := &SignatureResponse{
-		Label:      label.String(),
-		Parameters: params,
-	}
-
-	return sr, nil
+	return list.String(), params, nil
 }
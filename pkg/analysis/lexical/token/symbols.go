@@ -51,6 +51,12 @@ func (s *Symbol) SelectionRange() jpos.Range {
 	return s.selectionRange
 }
 
+// Children are the symbols declared in this symbol, e.g. the fields of an
+// object.
+func (s *Symbol) Children() []Symbol {
+	return s.children
+}
+
 type symbolVisitor struct{}
 
 func newSymbolVisitor() *symbolVisitor {
@@ -77,8 +83,15 @@ func (s *symbolVisitor) visit(n ast.Node) []Symbol {
 		syms = append(syms, s.visit(n.BranchFalse)...)
 	case *ast.DesugaredObject:
 		for _, field := range n.Fields {
-			syms = append(syms, s.visit(field.Name)...)
-			syms = append(syms, s.visit(field.Body)...)
+			sym, ok := fieldSymbol(n, field)
+			if !ok {
+				syms = append(syms, s.visit(field.Name)...)
+				syms = append(syms, s.visit(field.Body)...)
+				continue
+			}
+
+			sym.children = s.visit(fieldBody(field))
+			syms = append(syms, sym)
 		}
 	case *ast.Error:
 		syms = append(syms, s.visit(n.Expr)...)
@@ -100,20 +113,24 @@ func (s *symbolVisitor) visit(n ast.Node) []Symbol {
 	case *ast.LiteralString:
 	case *ast.Local:
 		for _, bind := range n.Binds {
-			if string(bind.Variable) != "$" {
-				// TODO figure out the exact ranges. The AST doesn't provide
-				sym := Symbol{
-					name:           string(bind.Variable),
-					kind:           symbolKind(bind.Body),
-					selectionRange: jpos.FromJsonnetRange(*bind.Body.Loc()),
-					enclosingRange: jpos.FromJsonnetRange(*bind.Body.Loc()),
-				}
-
-				syms = append(syms, sym)
+			// binds without a location were created while desugaring, e.g.
+			// `$` or object locals.
+			if bind.VarLoc.Begin.Line == 0 {
+				syms = append(syms, s.visit(bind.Body)...)
+				continue
 			}
 
-			syms = append(syms, s.visit(bind.Body)...)
+			name := jpos.FromJsonnetRange(bind.VarLoc)
+			sym := Symbol{
+				name:           string(bind.Variable),
+				detail:         functionDetail(bind.Body),
+				kind:           symbolKind(bind.Body),
+				selectionRange: name,
+				enclosingRange: declarationRange(name, bind.Body),
+				children:       s.visit(bind.Body),
+			}
 
+			syms = append(syms, sym)
 		}
 		syms = append(syms, s.visit(n.Body)...)
 	case *astext.Partial, *astext.PartialIndex:
@@ -132,6 +149,73 @@ func (s *symbolVisitor) visit(n ast.Node) []Symbol {
 	return syms
 }
 
+// fieldSymbol creates a symbol for an object field. Fields with computed
+// names don't have symbols.
+func fieldSymbol(o *ast.DesugaredObject, field ast.DesugaredObjectField) (Symbol, bool) {
+	name, err := fieldName(field)
+	if err != nil {
+		return Symbol{}, false
+	}
+
+	loc, ok := o.FieldLocs[name]
+	if !ok {
+		return Symbol{}, false
+	}
+
+	body := fieldBody(field)
+
+	kind := symbolKind(body)
+	if kind == lsp.SKFunction {
+		kind = lsp.SKMethod
+	}
+
+	visibility := astext.ObjectFieldVisibility(field.Hide)
+	if field.PlusSuper {
+		visibility = "+" + visibility
+	}
+
+	detail := visibility
+	if fn := functionDetail(body); fn != "" {
+		detail += " " + fn
+	}
+
+	selectionRange := jpos.FromJsonnetRange(loc)
+
+	return Symbol{
+		name:           name,
+		detail:         detail,
+		kind:           kind,
+		selectionRange: selectionRange,
+		enclosingRange: declarationRange(selectionRange, body),
+	}, true
+}
+
+// functionDetail is the signature of a function, e.g. `function(x, y=1)`.
+// It is blank if the node isn't a function.
+func functionDetail(n ast.Node) string {
+	fn, ok := n.(*ast.Function)
+	if !ok {
+		return ""
+	}
+
+	list, _, err := parameterList(fn)
+	if err != nil {
+		return "function"
+	}
+
+	return fmt.Sprintf("function(%s)", list)
+}
+
+// declarationRange is the range from a declared name to the end of its
+// value.
+func declarationRange(name jpos.Range, value ast.Node) jpos.Range {
+	if value == nil || value.Loc().End.Line == 0 {
+		return name
+	}
+
+	return jpos.NewRange(name.Start, jpos.FromJsonnetLocation(value.Loc().End))
+}
+
 // Symbols retrieves symbols from source.
 func Symbols(source string) ([]Symbol, error) {
 	node, err := ReadSource("symbols.jsonnet", source, nil)
@@ -22,8 +22,8 @@ func TestSymbols(t *testing.T) {
 				{
 					name:           "a",
 					kind:           lsp.SKString,
-					selectionRange: jpos.NewRange(jpos.New(1, 9), jpos.New(1, 12)),
-					enclosingRange: jpos.NewRange(jpos.New(1, 9), jpos.New(1, 12)),
+					selectionRange: jpos.NewRange(jpos.New(1, 7), jpos.New(1, 8)),
+					enclosingRange: jpos.NewRange(jpos.New(1, 7), jpos.New(1, 12)),
 				},
 			},
 		},
@@ -34,14 +34,14 @@ func TestSymbols(t *testing.T) {
 				{
 					name:           "a",
 					kind:           lsp.SKNumber,
-					selectionRange: jpos.NewRange(jpos.New(1, 9), jpos.New(1, 10)),
-					enclosingRange: jpos.NewRange(jpos.New(1, 9), jpos.New(1, 10)),
+					selectionRange: jpos.NewRange(jpos.New(1, 7), jpos.New(1, 8)),
+					enclosingRange: jpos.NewRange(jpos.New(1, 7), jpos.New(1, 10)),
 				},
 				{
 					name:           "b",
 					kind:           lsp.SKNumber,
-					selectionRange: jpos.NewRange(jpos.New(1, 14), jpos.New(1, 15)),
-					enclosingRange: jpos.NewRange(jpos.New(1, 14), jpos.New(1, 15)),
+					selectionRange: jpos.NewRange(jpos.New(1, 12), jpos.New(1, 13)),
+					enclosingRange: jpos.NewRange(jpos.New(1, 12), jpos.New(1, 15)),
 				},
 			},
 		},
@@ -52,14 +52,14 @@ func TestSymbols(t *testing.T) {
 				{
 					name:           "a",
 					kind:           lsp.SKNumber,
-					selectionRange: jpos.NewRange(jpos.New(1, 9), jpos.New(1, 10)),
-					enclosingRange: jpos.NewRange(jpos.New(1, 9), jpos.New(1, 10)),
+					selectionRange: jpos.NewRange(jpos.New(1, 7), jpos.New(1, 8)),
+					enclosingRange: jpos.NewRange(jpos.New(1, 7), jpos.New(1, 10)),
 				},
 				{
 					name:           "b",
 					kind:           lsp.SKNumber,
-					selectionRange: jpos.NewRange(jpos.New(1, 20), jpos.New(1, 21)),
-					enclosingRange: jpos.NewRange(jpos.New(1, 20), jpos.New(1, 21)),
+					selectionRange: jpos.NewRange(jpos.New(1, 18), jpos.New(1, 19)),
+					enclosingRange: jpos.NewRange(jpos.New(1, 18), jpos.New(1, 21)),
 				},
 			},
 		},
@@ -69,12 +69,41 @@ func TestSymbols(t *testing.T) {
 			expected: []Symbol{
 				{
 					name:           "id",
+					detail:         "function(x)",
 					kind:           lsp.SKFunction,
-					selectionRange: jpos.NewRange(jpos.New(1, 7), jpos.New(1, 16)),
+					selectionRange: jpos.NewRange(jpos.New(1, 7), jpos.New(1, 9)),
 					enclosingRange: jpos.NewRange(jpos.New(1, 7), jpos.New(1, 16)),
 				},
 			},
 		},
+		{
+			name:   "object",
+			source: "local o = {a: 1, b:: 'b'}; o",
+			expected: []Symbol{
+				{
+					name:           "o",
+					kind:           lsp.SKObject,
+					selectionRange: jpos.NewRange(jpos.New(1, 7), jpos.New(1, 8)),
+					enclosingRange: jpos.NewRange(jpos.New(1, 7), jpos.New(1, 26)),
+					children: []Symbol{
+						{
+							name:           "a",
+							detail:         ":",
+							kind:           lsp.SKNumber,
+							selectionRange: jpos.NewRange(jpos.New(1, 12), jpos.New(1, 13)),
+							enclosingRange: jpos.NewRange(jpos.New(1, 12), jpos.New(1, 16)),
+						},
+						{
+							name:           "b",
+							detail:         "::",
+							kind:           lsp.SKString,
+							selectionRange: jpos.NewRange(jpos.New(1, 18), jpos.New(1, 19)),
+							enclosingRange: jpos.NewRange(jpos.New(1, 18), jpos.New(1, 25)),
+						},
+					},
+				},
+			},
+		},
 	}
 
 	for _, tc := range cases {
@@ -87,6 +116,56 @@ func TestSymbols(t *testing.T) {
 	}
 }
 
+func TestSymbols_outline(t *testing.T) {
+	source := `{
+  local hidden = 1,
+  a: {
+    b::: true,
+    f(x, y=1):: x,
+  },
+  c+: [],
+}`
+
+	type outline struct {
+		name     string
+		detail   string
+		kind     lsp.SymbolKind
+		children []outline
+	}
+
+	var toOutline func([]Symbol) []outline
+	toOutline = func(symbols []Symbol) []outline {
+		var out []outline
+		for _, sym := range symbols {
+			out = append(out, outline{
+				name:     sym.Name(),
+				detail:   sym.Detail(),
+				kind:     sym.Kind(),
+				children: toOutline(sym.Children()),
+			})
+		}
+		return out
+	}
+
+	expected := []outline{
+		{
+			name:   "a",
+			detail: ":",
+			kind:   lsp.SKObject,
+			children: []outline{
+				{name: "b", detail: ":::", kind: lsp.SKBoolean},
+				{name: "f", detail: ":: function(x, y=1)", kind: lsp.SKMethod},
+			},
+		},
+		{name: "c", detail: "+:", kind: lsp.SKArray},
+	}
+
+	symbols, err := Symbols(source)
+	require.NoError(t, err)
+
+	assert.Equal(t, expected, toOutline(symbols))
+}
+
 func Test_symbolKind(t *testing.T) {
 	cases := []struct {
 		name     string
@@ -0,0 +1,6 @@
+{
+  name: 'base',
+  labels: {
+    app: 'base',
+  },
+}
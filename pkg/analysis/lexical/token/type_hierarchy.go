@@ -0,0 +1,383 @@
+package token
+
+import (
+	"io/ioutil"
+	"path/filepath"
+	"sort"
+
+	"github.com/tminor/jsonnet-language-server/pkg/lsp"
+	jpos "github.com/tminor/jsonnet-language-server/pkg/util/position"
+	"github.com/google/go-jsonnet/ast"
+)
+
+// TypeHierarchyItem is an object in a type hierarchy. Objects are locals
+// and object fields whose values are object literals or objects composed
+// with `+`. A file is an item for the object it evaluates to.
+type TypeHierarchyItem struct {
+	Name   string
+	Kind   lsp.SymbolKind
+	Detail string
+	// Location is the range from the object's name to the end of its value.
+	Location jpos.Location
+	// SelectionRange is the range of the object's name.
+	SelectionRange jpos.Range
+}
+
+// Declaration is the location of the item's name. It identifies the item.
+func (item *TypeHierarchyItem) Declaration() jpos.Location {
+	return jpos.NewLocation(item.Location.URI(), item.SelectionRange)
+}
+
+// PrepareTypeHierarchy returns the object declared or referred to at a
+// position.
+func PrepareTypeHierarchy(filename, source string, pos jpos.Position, nodeCache *NodeCache, libPaths []string) ([]TypeHierarchyItem, error) {
+	th := newTypeHierarchy(nodeCache, libPaths)
+
+	tg, err := th.graph(filename, source)
+	if err != nil {
+		return nil, err
+	}
+
+	decl, ok := tg.ri.at(pos)
+	if !ok {
+		return nil, nil
+	}
+
+	item, ok := th.object(tg.ri.declarationsOf(decl))
+	if !ok {
+		return nil, nil
+	}
+
+	return []TypeHierarchyItem{item}, nil
+}
+
+// Supertypes returns the objects an item is composed from. They are the
+// objects on the left of the last object in the composition which defines
+// the item, e.g. `a` and `b` in `a + b + {}`.
+func Supertypes(filename, source string, item TypeHierarchyItem, nodeCache *NodeCache, libPaths []string) ([]TypeHierarchyItem, error) {
+	th := newTypeHierarchy(nodeCache, libPaths)
+
+	tg, err := th.graph(filename, source)
+	if err != nil {
+		return nil, err
+	}
+
+	value, ok := tg.values[item.Declaration()]
+	if !ok {
+		return nil, nil
+	}
+
+	var items []TypeHierarchyItem
+	seen := make(map[jpos.Location]bool)
+
+	for _, operand := range supertypeOperands(value) {
+		super, ok := th.operand(tg, operand)
+		if !ok || seen[super.Declaration()] {
+			continue
+		}
+
+		seen[super.Declaration()] = true
+		items = append(items, super)
+	}
+
+	return items, nil
+}
+
+// Subtypes returns the objects in source which extend an item.
+func Subtypes(filename, source string, item TypeHierarchyItem, nodeCache *NodeCache, libPaths []string) ([]TypeHierarchyItem, error) {
+	th := newTypeHierarchy(nodeCache, libPaths)
+
+	tg, err := th.graph(filename, source)
+	if err != nil {
+		return nil, err
+	}
+
+	decl := item.Declaration()
+
+	refs := make(map[jpos.Location]bool)
+	for _, ref := range tg.ri.referencesTo(decl) {
+		refs[ref] = true
+	}
+
+	var items []TypeHierarchyItem
+	for _, comp := range tg.compositions {
+		for _, operand := range supertypeOperands(comp.node) {
+			if tg.refersTo(operand, decl, refs) {
+				items = append(items, comp.item)
+				break
+			}
+		}
+	}
+
+	sort.Slice(items, func(i, j int) bool {
+		a, b := items[i].SelectionRange.Start, items[j].SelectionRange.Start
+		return locationBefore(a.ToJsonnet(), b.ToJsonnet())
+	})
+
+	return items, nil
+}
+
+// supertypeOperands returns the operands of a composition except for the
+// last one, which is the object being defined.
+func supertypeOperands(n ast.Node) []ast.Node {
+	operands := compositionOperands(n)
+	if len(operands) < 2 {
+		return nil
+	}
+
+	return operands[:len(operands)-1]
+}
+
+// compositionOperands returns the operands of objects composed with `+` from
+// left to right.
+func compositionOperands(n ast.Node) []ast.Node {
+	b, ok := n.(*ast.Binary)
+	if !ok || b.Op != ast.BopPlus {
+		return []ast.Node{n}
+	}
+
+	return append(compositionOperands(b.Left), compositionOperands(b.Right)...)
+}
+
+// composition is an object composed with `+` which is the value of an item.
+type composition struct {
+	item TypeHierarchyItem
+	node ast.Node
+}
+
+// typeGraph is the objects declared in a file.
+type typeGraph struct {
+	filename     string
+	libPaths     []string
+	sg           *scopeGraph
+	ri           *referenceIndex
+	file         TypeHierarchyItem
+	decls        map[jpos.Location]TypeHierarchyItem
+	values       map[jpos.Location]ast.Node
+	compositions []composition
+}
+
+func newTypeGraph(filename, source string, nodeCache *NodeCache, libPaths []string) (*typeGraph, error) {
+	node, err := ReadSource(filename, source, nil)
+	if err != nil {
+		return nil, err
+	}
+
+	sg := scanScope(node, nodeCache)
+
+	tg := &typeGraph{
+		filename: filename,
+		libPaths: libPaths,
+		sg:       sg,
+		ri:       newReferenceIndex(filename, sg, libPaths, nodeCache),
+		file: TypeHierarchyItem{
+			Name:           filepath.Base(filename),
+			Kind:           lsp.SKFile,
+			Location:       jpos.NewLocation(filename, fileRange(source)),
+			SelectionRange: jpos.NewRangeFromCoords(1, 1, 1, 1),
+		},
+		decls:  make(map[jpos.Location]TypeHierarchyItem),
+		values: make(map[jpos.Location]ast.Node),
+	}
+
+	or := newObjectResolver(filename, sg, libPaths, nodeCache)
+
+	_, body := topLevelBinds(node)
+	tg.add(tg.file, body)
+
+	for n := range sg.parents {
+		switch n := n.(type) {
+		case *ast.Local:
+			for _, bind := range n.Binds {
+				if item, ok := tg.object(or, string(bind.Variable), bind.VarLoc, bind.Body); ok {
+					tg.add(item, bind.Body)
+				}
+			}
+		case *ast.DesugaredObject:
+			for _, field := range n.Fields {
+				name, err := fieldName(field)
+				if err != nil {
+					continue
+				}
+
+				body := fieldBody(field)
+				if item, ok := tg.object(or, name, n.FieldLocs[name], body); ok {
+					tg.add(item, body)
+				}
+			}
+		}
+	}
+
+	return tg, nil
+}
+
+// object creates an item for a local or a field if its value is an object.
+func (tg *typeGraph) object(or *objectResolver, name string, loc ast.LocationRange, value ast.Node) (TypeHierarchyItem, bool) {
+	if loc.Begin.Line == 0 {
+		return TypeHierarchyItem{}, false
+	}
+
+	switch value := value.(type) {
+	case *ast.DesugaredObject:
+	case *ast.Binary:
+		if value.Op != ast.BopPlus {
+			return TypeHierarchyItem{}, false
+		}
+
+		if _, err := or.layers(tg.sg, value); err != nil {
+			return TypeHierarchyItem{}, false
+		}
+	default:
+		return TypeHierarchyItem{}, false
+	}
+
+	selectionRange := jpos.FromJsonnetRange(loc)
+
+	return TypeHierarchyItem{
+		Name:           name,
+		Kind:           lsp.SKObject,
+		Location:       jpos.NewLocation(tg.filename, declarationRange(selectionRange, value)),
+		SelectionRange: selectionRange,
+	}, true
+}
+
+func (tg *typeGraph) add(item TypeHierarchyItem, value ast.Node) {
+	decl := item.Declaration()
+	tg.decls[decl] = item
+	tg.values[decl] = value
+
+	if len(compositionOperands(value)) > 1 {
+		tg.compositions = append(tg.compositions, composition{item: item, node: value})
+	}
+}
+
+// refersTo returns true if an operand refers to a declaration. refs are the
+// references to the declaration in the file.
+func (tg *typeGraph) refersTo(operand ast.Node, decl jpos.Location, refs map[jpos.Location]bool) bool {
+	if imp, ok := operand.(*ast.Import); ok {
+		path, err := resolveImport(tg.filename, imp.File.Value, tg.libPaths)
+		return err == nil && jpos.NewLocation(path, tg.file.SelectionRange) == decl
+	}
+
+	r, ok := operandName(operand)
+	if !ok {
+		return false
+	}
+
+	return refs[jpos.NewLocation(tg.filename, jpos.FromJsonnetRange(r))]
+}
+
+// operandName returns the range of the name an operand refers to an object
+// with, e.g. `b` in `a.b`.
+func operandName(operand ast.Node) (ast.LocationRange, bool) {
+	switch operand := operand.(type) {
+	case *ast.Var:
+		r := *operand.Loc()
+		return r, r.Begin.Line != 0
+	case *ast.Index:
+		name, ok := operand.Index.(*ast.LiteralString)
+		if !ok || operand.Loc().End.Line == 0 {
+			return ast.LocationRange{}, false
+		}
+
+		return indexedNameRange(*operand.Loc(), name)
+	case *ast.SuperIndex:
+		name, ok := operand.Index.(*ast.LiteralString)
+		if !ok || operand.Loc().End.Line == 0 {
+			return ast.LocationRange{}, false
+		}
+
+		return superIndexNameRange(*operand.Loc(), name)
+	default:
+		return ast.LocationRange{}, false
+	}
+}
+
+// typeHierarchy finds objects in files. Files other than the current file
+// are read from disk once.
+type typeHierarchy struct {
+	nodeCache *NodeCache
+	libPaths  []string
+	graphs    map[string]*typeGraph
+}
+
+func newTypeHierarchy(nodeCache *NodeCache, libPaths []string) *typeHierarchy {
+	return &typeHierarchy{
+		nodeCache: nodeCache,
+		libPaths:  libPaths,
+		graphs:    make(map[string]*typeGraph),
+	}
+}
+
+// graph returns the type graph for a file. If source is blank, the file is
+// read from disk.
+func (th *typeHierarchy) graph(filename, source string) (*typeGraph, error) {
+	if tg, ok := th.graphs[filename]; ok {
+		return tg, nil
+	}
+
+	if source == "" {
+		/* #nosec */
+		data, err := ioutil.ReadFile(filename)
+		if err != nil {
+			return nil, err
+		}
+
+		source = string(data)
+	}
+
+	tg, err := newTypeGraph(filename, source, th.nodeCache, th.libPaths)
+	if err != nil {
+		return nil, err
+	}
+
+	th.graphs[filename] = tg
+
+	return tg, nil
+}
+
+// object returns the first declaration which declares an object.
+func (th *typeHierarchy) object(decls []jpos.Location) (TypeHierarchyItem, bool) {
+	for i := range decls {
+		tg, err := th.graph(decls[i].URI(), "")
+		if err != nil {
+			continue
+		}
+
+		if item, ok := tg.decls[decls[i]]; ok {
+			return item, true
+		}
+	}
+
+	return TypeHierarchyItem{}, false
+}
+
+// operand returns the item an operand in a composition refers to. Imports
+// refer to the imported file.
+func (th *typeHierarchy) operand(tg *typeGraph, operand ast.Node) (TypeHierarchyItem, bool) {
+	if imp, ok := operand.(*ast.Import); ok {
+		path, err := resolveImport(tg.filename, imp.File.Value, th.libPaths)
+		if err != nil {
+			return TypeHierarchyItem{}, false
+		}
+
+		imported, err := th.graph(path, "")
+		if err != nil {
+			return TypeHierarchyItem{}, false
+		}
+
+		return imported.file, true
+	}
+
+	r, ok := operandName(operand)
+	if !ok {
+		return TypeHierarchyItem{}, false
+	}
+
+	decl, ok := tg.ri.at(jpos.FromJsonnetLocation(r.Begin))
+	if !ok {
+		return TypeHierarchyItem{}, false
+	}
+
+	return th.object(tg.ri.declarationsOf(decl))
+}
@@ -0,0 +1,157 @@
+package token
+
+import (
+	"testing"
+
+	"github.com/tminor/jsonnet-language-server/pkg/lsp"
+	jpos "github.com/tminor/jsonnet-language-server/pkg/util/position"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const typeHierarchySource = `local base = { name: 'base' };
+local env = base + { env: 'dev' };
+{
+  app: env + { replicas: 3 },
+  other: base + env + {},
+}`
+
+func typeHierarchyItem(filename, name string, kind lsp.SymbolKind, location, selectionRange jpos.Range) TypeHierarchyItem {
+	return TypeHierarchyItem{
+		Name:           name,
+		Kind:           kind,
+		Location:       jpos.NewLocation(filename, location),
+		SelectionRange: selectionRange,
+	}
+}
+
+var (
+	typeHierarchyBase = typeHierarchyItem("file.jsonnet", "base", lsp.SKObject,
+		jpos.NewRangeFromCoords(1, 7, 1, 30), jpos.NewRangeFromCoords(1, 7, 1, 11))
+	typeHierarchyEnv = typeHierarchyItem("file.jsonnet", "env", lsp.SKObject,
+		jpos.NewRangeFromCoords(2, 7, 2, 34), jpos.NewRangeFromCoords(2, 7, 2, 10))
+	typeHierarchyApp = typeHierarchyItem("file.jsonnet", "app", lsp.SKObject,
+		jpos.NewRangeFromCoords(4, 3, 4, 29), jpos.NewRangeFromCoords(4, 3, 4, 6))
+	typeHierarchyOther = typeHierarchyItem("file.jsonnet", "other", lsp.SKObject,
+		jpos.NewRangeFromCoords(5, 3, 5, 25), jpos.NewRangeFromCoords(5, 3, 5, 8))
+)
+
+func TestPrepareTypeHierarchy(t *testing.T) {
+	cases := []struct {
+		name     string
+		pos      jpos.Position
+		expected []TypeHierarchyItem
+	}{
+		{
+			name:     "local declaration",
+			pos:      jpos.New(1, 8),
+			expected: []TypeHierarchyItem{typeHierarchyBase},
+		},
+		{
+			name:     "local reference",
+			pos:      jpos.New(4, 8),
+			expected: []TypeHierarchyItem{typeHierarchyEnv},
+		},
+		{
+			name:     "field declaration",
+			pos:      jpos.New(5, 4),
+			expected: []TypeHierarchyItem{typeHierarchyOther},
+		},
+		{
+			name: "not an object",
+			pos:  jpos.New(1, 17),
+		},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			got, err := PrepareTypeHierarchy("file.jsonnet", typeHierarchySource, tc.pos, NewNodeCache(), nil)
+			require.NoError(t, err)
+
+			assert.Equal(t, tc.expected, got)
+		})
+	}
+}
+
+func TestSupertypes(t *testing.T) {
+	cases := []struct {
+		name     string
+		filename string
+		source   string
+		item     TypeHierarchyItem
+		expected []TypeHierarchyItem
+	}{
+		{
+			name:     "local",
+			filename: "file.jsonnet",
+			source:   typeHierarchySource,
+			item:     typeHierarchyEnv,
+			expected: []TypeHierarchyItem{typeHierarchyBase},
+		},
+		{
+			name:     "field",
+			filename: "file.jsonnet",
+			source:   typeHierarchySource,
+			item:     typeHierarchyOther,
+			expected: []TypeHierarchyItem{typeHierarchyBase, typeHierarchyEnv},
+		},
+		{
+			name:     "object literal",
+			filename: "file.jsonnet",
+			source:   typeHierarchySource,
+			item:     typeHierarchyBase,
+		},
+		{
+			name:     "import",
+			filename: "testdata/types.jsonnet",
+			source:   `(import 'references.libsonnet') + { name: 'app' }`,
+			item: typeHierarchyItem("testdata/types.jsonnet", "types.jsonnet", lsp.SKFile,
+				jpos.NewRangeFromCoords(1, 1, 1, 50), jpos.NewRangeFromCoords(1, 1, 1, 1)),
+			expected: []TypeHierarchyItem{
+				typeHierarchyItem("testdata/references.libsonnet", "references.libsonnet", lsp.SKFile,
+					jpos.NewRangeFromCoords(1, 1, 7, 1), jpos.NewRangeFromCoords(1, 1, 1, 1)),
+			},
+		},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			got, err := Supertypes(tc.filename, tc.source, tc.item, NewNodeCache(), nil)
+			require.NoError(t, err)
+
+			assert.Equal(t, tc.expected, got)
+		})
+	}
+}
+
+func TestSubtypes(t *testing.T) {
+	cases := []struct {
+		name     string
+		item     TypeHierarchyItem
+		expected []TypeHierarchyItem
+	}{
+		{
+			name:     "extended locally and in fields",
+			item:     typeHierarchyBase,
+			expected: []TypeHierarchyItem{typeHierarchyEnv, typeHierarchyOther},
+		},
+		{
+			name:     "extended in fields",
+			item:     typeHierarchyEnv,
+			expected: []TypeHierarchyItem{typeHierarchyApp, typeHierarchyOther},
+		},
+		{
+			name: "not extended",
+			item: typeHierarchyApp,
+		},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			got, err := Subtypes("file.jsonnet", typeHierarchySource, tc.item, NewNodeCache(), nil)
+			require.NoError(t, err)
+
+			assert.Equal(t, tc.expected, got)
+		})
+	}
+}
@@ -0,0 +1,312 @@
+package token
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	jpos "github.com/tminor/jsonnet-language-server/pkg/util/position"
+	"github.com/google/go-jsonnet/ast"
+)
+
+const (
+	// maxFieldSuggestions limits the number of field names suggested for an
+	// unknown field.
+	maxFieldSuggestions = 3
+	// maxObjectVariants limits the number of objects resolved for a node
+	// whose value depends on conditionals.
+	maxObjectVariants = 16
+)
+
+// UnknownField is an index of a field which does not exist in an object
+// whose fields are known.
+type UnknownField struct {
+	Name string
+	// Range is the range of the field name in the index.
+	Range jpos.Range
+	// Suggestions are the fields in the object with the closest names,
+	// closest first.
+	Suggestions []string
+}
+
+// Message describes the unknown field and the fields it may have meant.
+func (uf *UnknownField) Message() string {
+	msg := fmt.Sprintf("field %q does not exist", uf.Name)
+	if len(uf.Suggestions) == 0 {
+		return msg
+	}
+
+	quoted := make([]string, 0, len(uf.Suggestions))
+	for _, name := range uf.Suggestions {
+		quoted = append(quoted, fmt.Sprintf("%q", name))
+	}
+
+	return fmt.Sprintf("%s; did you mean %s?", msg, strings.Join(quoted, " or "))
+}
+
+// UnknownFields finds the indexes in source of fields which do not exist.
+// Objects are resolved statically from object literals, imports and the node
+// cache. Indexes of `self`, `super` and `$` are skipped because the object
+// may be extended with the field elsewhere. Objects which come from the
+// result of a function or which are only partly resolved are skipped. If
+// the object depends on conditionals, the field must be missing from every
+// object it can be.
+func UnknownFields(filename, source string, nodeCache *NodeCache, libPaths []string) ([]UnknownField, error) {
+	node, err := ReadSource(filename, source, nil)
+	if err != nil {
+		return nil, err
+	}
+
+	sg := scanScope(node, nodeCache)
+	or := newObjectResolver(filename, sg, libPaths, nodeCache)
+	lines := strings.Split(source, "\n")
+
+	var unknown []UnknownField
+
+	for n := range sg.parents {
+		index, ok := n.(*ast.Index)
+		if !ok || index.Loc().End.Line == 0 || isExtensible(index.Target) {
+			continue
+		}
+
+		name, ok := index.Index.(*ast.LiteralString)
+		if !ok {
+			continue
+		}
+
+		r := indexNameRange(*index.Loc(), name.Value)
+		if !sourceMatches(lines, r, name.Value) {
+			// the field is indexed with brackets.
+			continue
+		}
+
+		variants, ok := objectVariants(or, sg, index.Target)
+		if !ok {
+			continue
+		}
+
+		var fields []Field
+		known := false
+		for _, variant := range variants {
+			if _, ok := lookupField(variant, name.Value); ok {
+				known = true
+				break
+			}
+
+			fields = append(fields, variant...)
+		}
+
+		if known {
+			continue
+		}
+
+		unknown = append(unknown, UnknownField{
+			Name:        name.Value,
+			Range:       jpos.FromJsonnetRange(r),
+			Suggestions: fieldSuggestions(fields, name.Value),
+		})
+	}
+
+	sort.Slice(unknown, func(i, j int) bool {
+		a, b := unknown[i].Range.Start, unknown[j].Range.Start
+		return locationBefore(a.ToJsonnet(), b.ToJsonnet())
+	})
+
+	return unknown, nil
+}
+
+// objectVariants returns the fields of each object a node can be. Each
+// combination of branches through the conditionals the node depends on is
+// resolved. It returns false if any of the objects can't be resolved
+// statically or has fields whose names aren't known.
+func objectVariants(or *objectResolver, sg *scopeGraph, n ast.Node) ([][]Field, bool) {
+	defer func() { or.branches = nil }()
+
+	pending := []map[*ast.Conditional]bool{{}}
+	var variants [][]Field
+
+	for len(pending) > 0 {
+		if len(variants) == maxObjectVariants {
+			return nil, false
+		}
+
+		falseBranch := pending[0]
+		pending = pending[1:]
+
+		or.branches = &branchChoice{falseBranch: falseBranch}
+		or.partial = false
+
+		layers, err := or.layers(sg, n)
+		if err != nil || or.partial || !hasKnownFields(layers) {
+			return nil, false
+		}
+
+		variants = append(variants, or.fields(layers))
+
+		// conditionals which were followed for the first time took their
+		// true branch, so their false branch is resolved next.
+		chosen := make(map[*ast.Conditional]bool)
+		for c, v := range falseBranch {
+			chosen[c] = v
+		}
+
+		for _, c := range or.branches.followed {
+			if _, ok := falseBranch[c]; ok {
+				continue
+			}
+
+			next := make(map[*ast.Conditional]bool)
+			for k, v := range chosen {
+				next[k] = v
+			}
+			next[c] = true
+
+			pending = append(pending, next)
+			chosen[c] = false
+		}
+	}
+
+	return variants, true
+}
+
+// isExtensible returns true if a node is `self`, `super` or `$`, or is an
+// index of one of them.
+func isExtensible(n ast.Node) bool {
+	for {
+		switch t := n.(type) {
+		case *ast.Self, *ast.SuperIndex:
+			return true
+		case *ast.Index:
+			n = t.Target
+		default:
+			return isDollar(n)
+		}
+	}
+}
+
+// hasKnownFields returns true if every field name in layers is known
+// without evaluating it.
+func hasKnownFields(layers []objectLayer) bool {
+	for _, layer := range layers {
+		if layer.evaluated != nil {
+			for _, field := range layer.evaluated.Fields {
+				if field.Kind == ast.ObjectFieldExpr {
+					return false
+				}
+			}
+
+			continue
+		}
+
+		for _, field := range layer.object.Fields {
+			if _, err := fieldName(field); err != nil {
+				return false
+			}
+		}
+	}
+
+	return true
+}
+
+// sourceMatches returns true if the text at a range in a single line is
+// text.
+func sourceMatches(lines []string, r ast.LocationRange, text string) bool {
+	if r.Begin.Line < 1 || r.Begin.Line > len(lines) || r.Begin.Column < 1 {
+		return false
+	}
+
+	line := lines[r.Begin.Line-1]
+	begin := r.Begin.Column - 1
+	end := begin + len(text)
+
+	return end <= len(line) && line[begin:end] == text
+}
+
+// fieldSuggestions returns the names of fields which are close to name by
+// edit distance.
+func fieldSuggestions(fields []Field, name string) []string {
+	maxDistance := len(name) / 2
+	if maxDistance < 1 {
+		maxDistance = 1
+	}
+
+	type suggestion struct {
+		name     string
+		distance int
+	}
+
+	// fields can be repeated when they are from more than one object.
+	seen := make(map[string]bool)
+
+	var suggestions []suggestion
+	for _, field := range fields {
+		if seen[field.Name] {
+			continue
+		}
+		seen[field.Name] = true
+
+		d := editDistance(name, field.Name)
+		if d <= maxDistance {
+			suggestions = append(suggestions, suggestion{name: field.Name, distance: d})
+		}
+	}
+
+	sort.Slice(suggestions, func(i, j int) bool {
+		if suggestions[i].distance != suggestions[j].distance {
+			return suggestions[i].distance < suggestions[j].distance
+		}
+
+		return suggestions[i].name < suggestions[j].name
+	})
+
+	var names []string
+	for i := range suggestions {
+		if i == maxFieldSuggestions {
+			break
+		}
+
+		names = append(names, suggestions[i].name)
+	}
+
+	return names
+}
+
+// editDistance is the Levenshtein distance between two strings.
+func editDistance(a, b string) int {
+	ar, br := []rune(a), []rune(b)
+
+	prev := make([]int, len(br)+1)
+	cur := make([]int, len(br)+1)
+
+	for j := range prev {
+		prev[j] = j
+	}
+
+	for i := 1; i <= len(ar); i++ {
+		cur[0] = i
+
+		for j := 1; j <= len(br); j++ {
+			cost := 1
+			if ar[i-1] == br[j-1] {
+				cost = 0
+			}
+
+			cur[j] = minInt(prev[j]+1, cur[j-1]+1, prev[j-1]+cost)
+		}
+
+		prev, cur = cur, prev
+	}
+
+	return prev[len(br)]
+}
+
+func minInt(values ...int) int {
+	min := values[0]
+	for _, v := range values[1:] {
+		if v < min {
+			min = v
+		}
+	}
+
+	return min
+}
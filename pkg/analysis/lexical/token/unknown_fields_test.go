@@ -0,0 +1,120 @@
+package token
+
+import (
+	"testing"
+
+	jpos "github.com/tminor/jsonnet-language-server/pkg/util/position"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestUnknownFields(t *testing.T) {
+	source := "local o = { field: 1, fields:: 2, name: 'n' };\n" +
+		"local lib = import 'references.libsonnet';\n" +
+		"[o.feild, o.field, o['missing'], lib.nmae, lib.labels.ap, { a: self.b }]"
+
+	expected := []UnknownField{
+		{
+			Name:        "feild",
+			Range:       jpos.NewRangeFromCoords(3, 4, 3, 9),
+			Suggestions: []string{"field"},
+		},
+		{
+			Name:        "nmae",
+			Range:       jpos.NewRangeFromCoords(3, 38, 3, 42),
+			Suggestions: []string{"name"},
+		},
+		{
+			Name:        "ap",
+			Range:       jpos.NewRangeFromCoords(3, 55, 3, 57),
+			Suggestions: []string{"app"},
+		},
+	}
+
+	got, err := UnknownFields("testdata/file.jsonnet", source, NewNodeCache(), nil)
+	require.NoError(t, err)
+
+	assert.Equal(t, expected, got)
+}
+
+func TestUnknownFields_uncertain(t *testing.T) {
+	cases := []struct {
+		name     string
+		source   string
+		expected []UnknownField
+	}{
+		{
+			name:   "conditional",
+			source: "local o = if std.length([]) > 0 then { a: 1 } else { b: 1 };\n[o.a, o.b, o.c]",
+			expected: []UnknownField{
+				{
+					Name:        "c",
+					Range:       jpos.NewRangeFromCoords(2, 14, 2, 15),
+					Suggestions: []string{"a", "b"},
+				},
+			},
+		},
+		{
+			name:   "function result",
+			source: "local f(x) = { a: x };\nf(1).b",
+		},
+		{
+			name:   "partial value",
+			source: "function(p) ({ a: { x: 1 } } + { a+: p }).a.y",
+		},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			got, err := UnknownFields("file.jsonnet", tc.source, NewNodeCache(), nil)
+			require.NoError(t, err)
+
+			assert.Equal(t, tc.expected, got)
+		})
+	}
+}
+
+func TestUnknownField_Message(t *testing.T) {
+	cases := []struct {
+		name        string
+		suggestions []string
+		expected    string
+	}{
+		{
+			name:     "no suggestions",
+			expected: `field "feild" does not exist`,
+		},
+		{
+			name:        "suggestions",
+			suggestions: []string{"field", "fields"},
+			expected:    `field "feild" does not exist; did you mean "field" or "fields"?`,
+		},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			uf := UnknownField{Name: "feild", Suggestions: tc.suggestions}
+			assert.Equal(t, tc.expected, uf.Message())
+		})
+	}
+}
+
+func Test_editDistance(t *testing.T) {
+	cases := []struct {
+		a        string
+		b        string
+		expected int
+	}{
+		{a: "field", b: "field", expected: 0},
+		{a: "feild", b: "field", expected: 2},
+		{a: "nam", b: "name", expected: 1},
+		{a: "", b: "abc", expected: 3},
+		{a: "kitten", b: "sitting", expected: 3},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.a+"/"+tc.b, func(t *testing.T) {
+			assert.Equal(t, tc.expected, editDistance(tc.a, tc.b))
+		})
+	}
+}
@@ -1,5 +1,7 @@
 package token
 
+import "github.com/google/go-jsonnet/ast"
+
 func slicesEqual(a, b []string) bool {
 	if (a == nil) != (b == nil) {
 		return false
@@ -17,3 +19,63 @@ func slicesEqual(a, b []string) bool {
 
 	return true
 }
+
+// identifierRange is the range of an identifier which begins at a location.
+func identifierRange(begin ast.Location, name string) ast.LocationRange {
+	return ast.LocationRange{
+		Begin: begin,
+		End:   ast.Location{Line: begin.Line, Column: begin.Column + len(name)},
+	}
+}
+
+// indexNameRange is the range of the field name at the end of an index,
+// e.g. `b` in `a.b`.
+func indexNameRange(index ast.LocationRange, name string) ast.LocationRange {
+	end := index.End
+	return ast.LocationRange{
+		Begin: ast.Location{Line: end.Line, Column: end.Column - len(name)},
+		End:   end,
+	}
+}
+
+// indexedNameRange is the range of the field name in an index of a string,
+// e.g. `b` in `a.b` or `a['b']`. It returns false if the name isn't an
+// identifier or a plain quoted string.
+func indexedNameRange(index ast.LocationRange, name *ast.LiteralString) (ast.LocationRange, bool) {
+	if name.Loc().Begin.Line == 0 {
+		// the desugarer creates the string from the identifier in `a.b`.
+		return indexNameRange(index, name.Value), true
+	}
+
+	return quotedRange(name)
+}
+
+// superIndexNameRange is the range of the field name in an index of
+// `super`, e.g. `b` in `super.b` or `super['b']`. The range of the index is
+// the range of `super`.
+func superIndexNameRange(index ast.LocationRange, name *ast.LiteralString) (ast.LocationRange, bool) {
+	if name.Loc().Begin.Line == 0 {
+		// the desugarer creates the string from the identifier after the
+		// dot.
+		begin := ast.Location{Line: index.End.Line, Column: index.End.Column + 1}
+		return identifierRange(begin, name.Value), true
+	}
+
+	return quotedRange(name)
+}
+
+// quotedRange is the range of the text between the quotes of a string. It
+// returns false if the string isn't its value in quotes, e.g. a verbatim
+// string or a string with escapes, because the desugarer doesn't keep the
+// kind of a string.
+func quotedRange(s *ast.LiteralString) (ast.LocationRange, bool) {
+	r := *s.Loc()
+	if r.Begin.Line != r.End.Line || r.End.Column-r.Begin.Column != len(s.Value)+2 {
+		return ast.LocationRange{}, false
+	}
+
+	return ast.LocationRange{
+		Begin: ast.Location{Line: r.Begin.Line, Column: r.Begin.Column + 1},
+		End:   ast.Location{Line: r.End.Line, Column: r.End.Column - 1},
+	}, true
+}
@@ -0,0 +1,158 @@
+package token
+
+import (
+	"strings"
+
+	"github.com/tminor/jsonnet-language-server/pkg/lsp"
+	jpos "github.com/tminor/jsonnet-language-server/pkg/util/position"
+	"github.com/google/go-jsonnet/ast"
+)
+
+const (
+	// maxExportDepth limits how deep nested fields are exported.
+	maxExportDepth = 8
+)
+
+// WorkspaceSymbol is a declaration in a file which can be searched for from
+// anywhere in the workspace.
+type WorkspaceSymbol struct {
+	Name string
+	// Container is the path of the object containing a field, e.g.
+	// `apps.v1.deployment` for `new`. It is blank for top level locals
+	// and fields.
+	Container string
+	Kind      lsp.SymbolKind
+	Location  jpos.Location
+}
+
+// QualifiedName is the symbol's name including its container.
+func (ws *WorkspaceSymbol) QualifiedName() string {
+	if ws.Container == "" {
+		return ws.Name
+	}
+
+	return ws.Container + "." + ws.Name
+}
+
+// ExportedSymbols returns the top level locals of a file and the fields
+// of the object it evaluates to. Fields of nested objects are included,
+// so fields in libraries can be found by their path.
+func ExportedSymbols(filename, source string) ([]WorkspaceSymbol, error) {
+	node, err := ReadSource(filename, source, nil)
+	if err != nil {
+		return nil, err
+	}
+
+	var symbols []WorkspaceSymbol
+
+	binds, body := topLevelBinds(node)
+	for _, bind := range binds {
+		symbols = append(symbols, WorkspaceSymbol{
+			Name:     string(bind.Variable),
+			Kind:     symbolKind(bind.Body),
+			Location: jpos.LocationFromJsonnet(bind.VarLoc),
+		})
+	}
+
+	return append(symbols, exportedFields(body)...), nil
+}
+
+// topLevelBinds returns the locals declared at the top of a file and the
+// node they are the scope of. Binds created while desugaring are skipped.
+func topLevelBinds(node ast.Node) ([]ast.LocalBind, ast.Node) {
+	var binds []ast.LocalBind
+
+	for {
+		local, ok := node.(*ast.Local)
+		if !ok {
+			return binds, node
+		}
+
+		for _, bind := range local.Binds {
+			if bind.VarLoc.Begin.Line == 0 {
+				continue
+			}
+
+			binds = append(binds, bind)
+		}
+
+		node = local.Body
+	}
+}
+
+// exportedFields returns the fields of the objects a node is composed of.
+func exportedFields(n ast.Node) []WorkspaceSymbol {
+	var symbols []WorkspaceSymbol
+
+	walkExportedFields(n, nil, 0, func(ef exportedField) {
+		kind := symbolKind(ef.body)
+		if kind == lsp.SKFunction {
+			kind = lsp.SKMethod
+		}
+
+		symbols = append(symbols, WorkspaceSymbol{
+			Name:      ef.name,
+			Container: strings.Join(ef.container, "."),
+			Kind:      kind,
+			Location:  jpos.LocationFromJsonnet(ef.loc),
+		})
+	})
+
+	return symbols
+}
+
+// exportedField is a field in the object a file evaluates to.
+type exportedField struct {
+	// container is the path of the object containing the field.
+	container []string
+	name      string
+	loc       ast.LocationRange
+	body      ast.Node
+}
+
+// path is the path to the field from the file's object.
+func (ef *exportedField) path() []string {
+	return append(append([]string{}, ef.container...), ef.name)
+}
+
+// walkExportedFields calls fn for the fields of the objects a node is
+// composed of. Fields of nested objects are walked after their parent.
+func walkExportedFields(n ast.Node, container []string, depth int, fn func(exportedField)) {
+	if depth > maxExportDepth {
+		return
+	}
+
+	switch n := n.(type) {
+	case *ast.Binary:
+		if n.Op != ast.BopPlus {
+			return
+		}
+
+		walkExportedFields(n.Left, container, depth, fn)
+		walkExportedFields(n.Right, container, depth, fn)
+	case *ast.Local:
+		walkExportedFields(n.Body, container, depth, fn)
+	case *ast.DesugaredObject:
+		for _, field := range n.Fields {
+			name, err := fieldName(field)
+			if err != nil {
+				continue
+			}
+
+			loc, ok := n.FieldLocs[name]
+			if !ok {
+				continue
+			}
+
+			ef := exportedField{
+				container: container,
+				name:      name,
+				loc:       loc,
+				body:      fieldBody(field),
+			}
+
+			fn(ef)
+			walkExportedFields(ef.body, ef.path(), depth+1, fn)
+		}
+	}
+}
@@ -0,0 +1,49 @@
+package token
+
+import (
+	"testing"
+
+	"github.com/tminor/jsonnet-language-server/pkg/lsp"
+	jpos "github.com/tminor/jsonnet-language-server/pkg/util/position"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestExportedSymbols(t *testing.T) {
+	source := `local util = {};
+{
+  apps:: {
+    deployment:: {
+      new(name):: {},
+    },
+  },
+} + {
+  version: 1,
+}`
+
+	symbols, err := ExportedSymbols("/lib/k.libsonnet", source)
+	require.NoError(t, err)
+
+	type symbol struct {
+		name string
+		kind lsp.SymbolKind
+		pos  jpos.Position
+	}
+
+	var got []symbol
+	for _, s := range symbols {
+		r := s.Location.Range()
+		got = append(got, symbol{name: s.QualifiedName(), kind: s.Kind, pos: r.Start})
+		assert.Equal(t, "/lib/k.libsonnet", s.Location.URI())
+	}
+
+	expected := []symbol{
+		{name: "util", kind: lsp.SKObject, pos: jpos.New(1, 7)},
+		{name: "apps", kind: lsp.SKObject, pos: jpos.New(3, 3)},
+		{name: "apps.deployment", kind: lsp.SKObject, pos: jpos.New(4, 5)},
+		{name: "apps.deployment.new", kind: lsp.SKMethod, pos: jpos.New(5, 7)},
+		{name: "version", kind: lsp.SKNumber, pos: jpos.New(9, 3)},
+	}
+
+	assert.Equal(t, expected, got)
+}
@@ -5,8 +5,10 @@ import (
 	"encoding/json"
 	"fmt"
 	"io/ioutil"
+	"strings"
 
 	"github.com/tminor/jsonnet-language-server/pkg/analysis/lexical/token"
+	"github.com/tminor/jsonnet-language-server/pkg/langserver"
 	"github.com/tminor/jsonnet-language-server/pkg/lsp"
 	"github.com/tminor/jsonnet-language-server/pkg/tracing"
 	"github.com/tminor/jsonnet-language-server/pkg/util/uri"
@@ -18,16 +20,37 @@ const (
 	// JsonnetLibPaths are jsonnet lib paths.
 	JsonnetLibPaths = "jsonnet.libPaths"
 
+	// Snippets are custom completion snippets.
+	Snippets = "jsonnet.snippets"
+
+	// InlayHints configures inlay hints.
+	InlayHints = "jsonnet.inlayHints"
+
 	// TextDocumentUpdates are text document updates.
 	TextDocumentUpdates = "textDocument.update"
 )
 
+var (
+	// DefaultInlayHints are the inlay hints shown if they are not
+	// configured. Local values are evaluated, so they are opt in.
+	DefaultInlayHints = token.InlayHintOptions{
+		ParameterNames: true,
+	}
+)
+
 // Config is configuration setting for the server.
 type Config struct {
-	textDocuments   map[string]TextDocument
-	jsonnetLibPaths []string
-	nodeCache       *token.NodeCache
-	dispatchers     map[string]*Dispatcher
+	textDocuments      map[string]TextDocument
+	jsonnetLibPaths    []string
+	snippets           []langserver.Snippet
+	inlayHints         token.InlayHintOptions
+	clientCapabilities lsp.ClientCapabilities
+	frecency           *langserver.Frecency
+	semanticTokens     *langserver.SemanticTokensCache
+	symbolIndex        *langserver.SymbolIndex
+	importIndex        *langserver.ImportIndex
+	nodeCache          *token.NodeCache
+	dispatchers        map[string]*Dispatcher
 }
 
 // New creates an instance of Config.
@@ -35,11 +58,58 @@ func New() *Config {
 	return &Config{
 		textDocuments:   make(map[string]TextDocument),
 		jsonnetLibPaths: make([]string, 0),
+		snippets:        langserver.DefaultSnippets,
+		inlayHints:      DefaultInlayHints,
+		frecency:        langserver.NewFrecency(),
+		semanticTokens:  langserver.NewSemanticTokensCache(),
+		symbolIndex:     langserver.NewSymbolIndex(),
+		importIndex:     langserver.NewImportIndex(),
 		nodeCache:       token.NewNodeCache(),
 		dispatchers:     map[string]*Dispatcher{},
 	}
 }
 
+// ClientCapabilities returns the capabilities of the client.
+func (c *Config) ClientCapabilities() lsp.ClientCapabilities {
+	return c.clientCapabilities
+}
+
+// SetClientCapabilities sets the capabilities of the client.
+func (c *Config) SetClientCapabilities(cc lsp.ClientCapabilities) {
+	c.clientCapabilities = cc
+}
+
+// Frecency returns the usage statistics for completions.
+func (c *Config) Frecency() *langserver.Frecency {
+	return c.frecency
+}
+
+// SemanticTokens returns the semantic tokens sent to the client.
+func (c *Config) SemanticTokens() *langserver.SemanticTokensCache {
+	return c.semanticTokens
+}
+
+// SymbolIndex returns the index of symbols in the workspace.
+func (c *Config) SymbolIndex() *langserver.SymbolIndex {
+	return c.symbolIndex
+}
+
+// ImportIndex returns the index of the files which import each file in the
+// workspace.
+func (c *Config) ImportIndex() *langserver.ImportIndex {
+	return c.importIndex
+}
+
+// Snippets returns completion snippets.
+func (c *Config) Snippets() []langserver.Snippet {
+	return c.snippets
+}
+
+// InlayHints returns the inlay hint options.
+func (c *Config) InlayHints() token.InlayHintOptions {
+	return c.inlayHints
+}
+
 // NodeCache returns the node cache.
 func (c *Config) NodeCache() *token.NodeCache {
 	return c.nodeCache
@@ -155,6 +225,20 @@ func (c *Config) UpdateClientConfiguration(ctx context.Context, update map[strin
 
 			c.jsonnetLibPaths = paths
 			c.dispatch(ctx, JsonnetLibPaths, paths)
+		case Snippets:
+			snippets, err := interfaceToSnippets(v)
+			if err != nil {
+				return errors.Wrapf(err, "setting %q", Snippets)
+			}
+
+			c.snippets = langserver.MergeSnippets(langserver.DefaultSnippets, snippets)
+		case InlayHints:
+			options, err := interfaceToInlayHints(v)
+			if err != nil {
+				return errors.Wrapf(err, "setting %q", InlayHints)
+			}
+
+			c.inlayHints = options
 		default:
 			return errors.Errorf("setting %q is unknown to the jsonnet language server", k)
 		}
@@ -203,3 +287,83 @@ func interfaceToStrings(v interface{}) ([]string, error) {
 		return nil, errors.Errorf("unable to convert %T to array of strings", v)
 	}
 }
+
+// interfaceToSnippets converts snippet configuration to snippets. Each
+// snippet is an object with a label, an optional detail, and a body. The
+// body is either a string or an array of lines.
+func interfaceToSnippets(v interface{}) ([]langserver.Snippet, error) {
+	items, ok := v.([]interface{})
+	if !ok {
+		return nil, errors.Errorf("unable to convert %T to array of snippets", v)
+	}
+
+	var snippets []langserver.Snippet
+	for _, item := range items {
+		m, ok := item.(map[string]interface{})
+		if !ok {
+			return nil, errors.Errorf("snippet was not an object")
+		}
+
+		var snippet langserver.Snippet
+
+		if snippet.Label, ok = m["label"].(string); !ok {
+			return nil, errors.Errorf("snippet label was not a string")
+		}
+
+		if detail, ok := m["detail"]; ok {
+			if snippet.Detail, ok = detail.(string); !ok {
+				return nil, errors.Errorf("snippet %q detail was not a string", snippet.Label)
+			}
+		}
+
+		switch body := m["body"].(type) {
+		case string:
+			snippet.Body = body
+		default:
+			lines, err := interfaceToStrings(body)
+			if err != nil {
+				return nil, errors.Wrapf(err, "snippet %q body", snippet.Label)
+			}
+
+			snippet.Body = strings.Join(lines, "\n")
+		}
+
+		if err := snippet.Validate(); err != nil {
+			return nil, err
+		}
+
+		snippets = append(snippets, snippet)
+	}
+
+	return snippets, nil
+}
+
+// interfaceToInlayHints converts inlay hint configuration to options. The
+// configuration is an object which enables `parameterNames` and
+// `localValues`. Options which aren't set keep their default.
+func interfaceToInlayHints(v interface{}) (token.InlayHintOptions, error) {
+	options := DefaultInlayHints
+
+	m, ok := v.(map[string]interface{})
+	if !ok {
+		return options, errors.Errorf("unable to convert %T to inlay hint options", v)
+	}
+
+	settings := map[string]*bool{
+		"parameterNames": &options.ParameterNames,
+		"localValues":    &options.LocalValues,
+	}
+
+	for k, v := range m {
+		setting, ok := settings[k]
+		if !ok {
+			return options, errors.Errorf("inlay hint option %q is unknown", k)
+		}
+
+		if *setting, ok = v.(bool); !ok {
+			return options, errors.Errorf("inlay hint option %q was not a boolean", k)
+		}
+	}
+
+	return options, nil
+}
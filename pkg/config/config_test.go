@@ -4,6 +4,8 @@ import (
 	"context"
 	"testing"
 
+	"github.com/tminor/jsonnet-language-server/pkg/analysis/lexical/token"
+	"github.com/tminor/jsonnet-language-server/pkg/langserver"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
 )
@@ -43,6 +45,57 @@ func TestConfig_UpdateClientConfiguration(t *testing.T) {
 			},
 			isErr: true,
 		},
+		{
+			name: "update snippets",
+			update: map[string]interface{}{
+				"jsonnet.snippets": []interface{}{
+					map[string]interface{}{
+						"label":  "local",
+						"detail": "local variable",
+						"body":   []interface{}{"local ${1:name} =", "  ${0};"},
+					},
+				},
+			},
+			key: func(c *Config) interface{} {
+				snippets := c.Snippets()
+				return snippets[len(snippets)-1]
+			},
+			expected: langserver.Snippet{
+				Label:  "local",
+				Detail: "local variable",
+				Body:   "local ${1:name} =\n  ${0};",
+			},
+		},
+		{
+			name: "snippet without body",
+			update: map[string]interface{}{
+				"jsonnet.snippets": []interface{}{
+					map[string]interface{}{"label": "local"},
+				},
+			},
+			isErr: true,
+		},
+		{
+			name: "update inlay hints",
+			update: map[string]interface{}{
+				"jsonnet.inlayHints": map[string]interface{}{
+					"localValues": true,
+				},
+			},
+			key: func(c *Config) interface{} {
+				return c.InlayHints()
+			},
+			expected: token.InlayHintOptions{ParameterNames: true, LocalValues: true},
+		},
+		{
+			name: "invalid inlay hint option",
+			update: map[string]interface{}{
+				"jsonnet.inlayHints": map[string]interface{}{
+					"localValues": "yes",
+				},
+			},
+			isErr: true,
+		},
 		{
 			name: "unknown setting",
 			update: map[string]interface{}{
@@ -17,18 +17,22 @@ import (
 // CompletionAction is an action performed on a completion match.
 type CompletionAction func(ctx context.Context, pos position.Position, path, source string) ([]lsp.CompletionItem, error)
 
-// CompletionMatcher can register multiple terms to complete against.
+type completionMatch struct {
+	re     *regexp.Regexp
+	action CompletionAction
+}
+
+// CompletionMatcher can register multiple terms to complete against. Terms
+// are matched in the order they were registered.
 type CompletionMatcher struct {
-	store     map[*regexp.Regexp]CompletionAction
+	store     []completionMatch
 	mu        sync.Mutex
 	nodeCache *token.NodeCache
 }
 
 // NewCompletionMatcher creates an instance of CompletionMatchers.
 func NewCompletionMatcher() *CompletionMatcher {
-	return &CompletionMatcher{
-		store: make(map[*regexp.Regexp]CompletionAction),
-	}
+	return &CompletionMatcher{}
 }
 
 // Register registers terms for the matcher
@@ -42,7 +46,7 @@ func (cm *CompletionMatcher) Register(term string, fn CompletionAction) error {
 		return err
 	}
 
-	cm.store[re] = fn
+	cm.store = append(cm.store, completionMatch{re: re, action: fn})
 
 	return nil
 }
@@ -61,14 +65,14 @@ func (cm *CompletionMatcher) Match(ctx context.Context, pos position.Position, p
 		return nil, err
 	}
 
-	for re, m := range cm.store {
+	for _, m := range cm.store {
 		span.LogFields(
 			log.String("match.text", matched),
-			log.String("match.regex", re.String()),
+			log.String("match.regex", m.re.String()),
 		)
-		match := re.FindStringSubmatch(matched)
+		match := m.re.FindStringSubmatch(matched)
 		if match != nil {
-			return m(ctx, pos, path, matched)
+			return m.action(ctx, pos, path, matched)
 		}
 	}
 
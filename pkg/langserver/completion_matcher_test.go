@@ -114,3 +114,28 @@ func TestCompletionMatchers_proceeding_ender(t *testing.T) {
 
 	assert.Equal(t, resp, list)
 }
+
+func TestCompletionMatchers_registration_order(t *testing.T) {
+	pos := position.New(1, 18)
+
+	cm := NewCompletionMatcher()
+
+	first := []lsp.CompletionItem{{Label: "first"}}
+	second := []lsp.CompletionItem{{Label: "second"}}
+
+	err := cm.Register(`import\s+"[^"]*`, func(ctx context.Context, p position.Position, path, source string) ([]lsp.CompletionItem, error) {
+		return first, nil
+	})
+	require.NoError(t, err)
+
+	err = cm.Register(`\w+\.`, func(ctx context.Context, p position.Position, path, source string) ([]lsp.CompletionItem, error) {
+		return second, nil
+	})
+	require.NoError(t, err)
+
+	ctx := context.Background()
+	list, err := cm.Match(ctx, pos, "file.jsonnet", `import "lib/foo.`)
+	require.NoError(t, err)
+
+	assert.Equal(t, first, list)
+}
@@ -0,0 +1,70 @@
+package langserver
+
+import (
+	"math"
+	"sync"
+	"time"
+)
+
+const (
+	// frecencyHalfLife is the time it takes for a use to lose half of its
+	// weight.
+	frecencyHalfLife = 72 * time.Hour
+)
+
+type frecencyEntry struct {
+	score float64
+	last  time.Time
+}
+
+// Frecency tracks how frequently and how recently completions were used.
+type Frecency struct {
+	mu      sync.Mutex
+	entries map[string]frecencyEntry
+	now     func() time.Time
+}
+
+// NewFrecency creates an instance of Frecency.
+func NewFrecency() *Frecency {
+	return &Frecency{
+		entries: make(map[string]frecencyEntry),
+		now:     time.Now,
+	}
+}
+
+// Record records a use of key with a weight.
+func (f *Frecency) Record(key string, weight float64) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	now := f.now()
+
+	entry := f.entries[key]
+	entry.score = f.decay(entry, now) + weight
+	entry.last = now
+
+	f.entries[key] = entry
+}
+
+// Score returns the frecency score for key. Keys which have not been used
+// have a score of 0.
+func (f *Frecency) Score(key string) float64 {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	entry, ok := f.entries[key]
+	if !ok {
+		return 0
+	}
+
+	return f.decay(entry, f.now())
+}
+
+func (f *Frecency) decay(entry frecencyEntry, now time.Time) float64 {
+	if entry.score == 0 {
+		return 0
+	}
+
+	elapsed := now.Sub(entry.last)
+	return entry.score * math.Pow(0.5, float64(elapsed)/float64(frecencyHalfLife))
+}
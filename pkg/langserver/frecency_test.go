@@ -0,0 +1,32 @@
+package langserver
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestFrecency(t *testing.T) {
+	now := time.Date(2018, 1, 1, 0, 0, 0, 0, time.UTC)
+
+	f := NewFrecency()
+	f.now = func() time.Time { return now }
+
+	assert.Equal(t, 0.0, f.Score("a"))
+
+	f.Record("a", 1)
+	f.Record("a", 1)
+	f.Record("b", 1)
+
+	assert.Equal(t, 2.0, f.Score("a"))
+	assert.Equal(t, 1.0, f.Score("b"))
+
+	now = now.Add(frecencyHalfLife)
+
+	assert.InDelta(t, 1.0, f.Score("a"), 0.0001)
+	assert.InDelta(t, 0.5, f.Score("b"), 0.0001)
+
+	f.Record("b", 1)
+	assert.InDelta(t, 1.5, f.Score("b"), 0.0001)
+}
@@ -0,0 +1,82 @@
+package langserver
+
+import (
+	"unicode"
+)
+
+const (
+	fuzzyMatchScore       = 10
+	fuzzyConsecutiveBonus = 15
+	fuzzyBoundaryBonus    = 20
+	fuzzyPrefixBonus      = 30
+	fuzzyCaseBonus        = 1
+	fuzzyGapPenalty       = 1
+)
+
+// FuzzyMatch matches a pattern against a candidate. The characters in the
+// pattern must appear in order in the candidate, ignoring case. The score is
+// higher for matches at the start of the candidate, at word boundaries, and
+// for consecutive characters. An empty pattern matches everything with a
+// score of 0.
+func FuzzyMatch(pattern, candidate string) (int, bool) {
+	if pattern == "" {
+		return 0, true
+	}
+
+	p := []rune(pattern)
+	c := []rune(candidate)
+
+	score := 0
+	pi := 0
+	last := -1
+
+	for ci := 0; ci < len(c) && pi < len(p); ci++ {
+		if unicode.ToLower(c[ci]) != unicode.ToLower(p[pi]) {
+			continue
+		}
+
+		score += fuzzyMatchScore
+
+		switch {
+		case ci == 0:
+			score += fuzzyPrefixBonus
+		case last == ci-1:
+			score += fuzzyConsecutiveBonus
+		case isWordBoundary(c, ci):
+			score += fuzzyBoundaryBonus
+		}
+
+		if last >= 0 {
+			score -= (ci - last - 1) * fuzzyGapPenalty
+		}
+
+		if c[ci] == p[pi] {
+			score += fuzzyCaseBonus
+		}
+
+		last = ci
+		pi++
+	}
+
+	if pi < len(p) {
+		return 0, false
+	}
+
+	// prefer shorter candidates when the match is otherwise the same.
+	score -= len(c) - len(p)
+
+	return score, true
+}
+
+// isWordBoundary returns true if the rune at i starts a word, e.g. the `b`
+// in `foo_bar` or `fooBar`.
+func isWordBoundary(c []rune, i int) bool {
+	prev := c[i-1]
+	cur := c[i]
+
+	if !unicode.IsLetter(prev) && !unicode.IsDigit(prev) {
+		return true
+	}
+
+	return unicode.IsLower(prev) && unicode.IsUpper(cur)
+}
@@ -0,0 +1,55 @@
+package langserver
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestFuzzyMatch(t *testing.T) {
+	cases := []struct {
+		name      string
+		pattern   string
+		candidate string
+		isMatch   bool
+	}{
+		{name: "empty pattern", pattern: "", candidate: "foo", isMatch: true},
+		{name: "prefix", pattern: "fo", candidate: "foo", isMatch: true},
+		{name: "subsequence", pattern: "dpl", candidate: "deployment", isMatch: true},
+		{name: "ignores case", pattern: "FB", candidate: "fooBar", isMatch: true},
+		{name: "out of order", pattern: "of", candidate: "foo", isMatch: false},
+		{name: "longer than candidate", pattern: "fooo", candidate: "foo", isMatch: false},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			_, ok := FuzzyMatch(tc.pattern, tc.candidate)
+			assert.Equal(t, tc.isMatch, ok)
+		})
+	}
+}
+
+func TestFuzzyMatch_ranking(t *testing.T) {
+	cases := []struct {
+		name    string
+		pattern string
+		better  string
+		worse   string
+	}{
+		{name: "prefix over inner match", pattern: "con", better: "container", worse: "iconic"},
+		{name: "word boundary over inner match", pattern: "bar", better: "foo_bar", worse: "fobaxr"},
+		{name: "camel case boundary", pattern: "fb", better: "fooBar", worse: "foobar"},
+		{name: "shorter candidate", pattern: "foo", better: "foo", worse: "foobar"},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			better, ok := FuzzyMatch(tc.pattern, tc.better)
+			assert.True(t, ok)
+			worse, ok := FuzzyMatch(tc.pattern, tc.worse)
+			assert.True(t, ok)
+
+			assert.True(t, better > worse, "expected %d > %d", better, worse)
+		})
+	}
+}
@@ -0,0 +1,142 @@
+package langserver
+
+import (
+	"io/ioutil"
+	"path/filepath"
+	"sort"
+	"sync"
+
+	"github.com/tminor/jsonnet-language-server/pkg/analysis/lexical/token"
+)
+
+// ImportIndex is a reverse import index for the Jsonnet files in the
+// workspace. It records the files each file imports, so the files which
+// import a file can be found without reading the workspace again. Files are
+// indexed individually, so the index can be updated as files change.
+type ImportIndex struct {
+	mu        sync.RWMutex
+	imports   map[string][]string
+	importers map[string]map[string]bool
+}
+
+// NewImportIndex creates an instance of ImportIndex.
+func NewImportIndex() *ImportIndex {
+	return &ImportIndex{
+		imports:   make(map[string][]string),
+		importers: make(map[string]map[string]bool),
+	}
+}
+
+// AddDir indexes the Jsonnet files in a directory and its subdirectories.
+// Hidden directories and files which can't be read are skipped. Entries
+// which can't be listed are reported in the error after the rest of the
+// directory is indexed.
+func (ii *ImportIndex) AddDir(dir string, libPaths []string) error {
+	return walkJsonnetFiles(dir, func(path string) {
+		_ = ii.Update(path, libPaths)
+	})
+}
+
+// Update reads a file from disk and indexes it.
+func (ii *ImportIndex) Update(path string, libPaths []string) error {
+	/* #nosec */
+	data, err := ioutil.ReadFile(path)
+	if err != nil {
+		return err
+	}
+
+	return ii.UpdateSource(path, string(data), libPaths)
+}
+
+// UpdateSource indexes the imports in a file's source. Imports which can't
+// be resolved are ignored.
+func (ii *ImportIndex) UpdateSource(path, source string, libPaths []string) error {
+	links, err := token.ImportLinks(path, source, libPaths)
+	if err != nil {
+		return err
+	}
+
+	seen := make(map[string]bool)
+	var imports []string
+	for _, link := range links {
+		if !link.IsResolved() {
+			continue
+		}
+
+		imported := filepath.Clean(link.Path)
+		if seen[imported] {
+			continue
+		}
+
+		seen[imported] = true
+		imports = append(imports, imported)
+	}
+
+	ii.mu.Lock()
+	defer ii.mu.Unlock()
+
+	path = filepath.Clean(path)
+	ii.remove(path)
+
+	ii.imports[path] = imports
+	for _, imported := range imports {
+		if ii.importers[imported] == nil {
+			ii.importers[imported] = make(map[string]bool)
+		}
+
+		ii.importers[imported][path] = true
+	}
+
+	return nil
+}
+
+// Remove removes a file from the index.
+func (ii *ImportIndex) Remove(path string) {
+	ii.mu.Lock()
+	defer ii.mu.Unlock()
+
+	ii.remove(filepath.Clean(path))
+}
+
+func (ii *ImportIndex) remove(path string) {
+	for _, imported := range ii.imports[path] {
+		delete(ii.importers[imported], path)
+		if len(ii.importers[imported]) == 0 {
+			delete(ii.importers, imported)
+		}
+	}
+
+	delete(ii.imports, path)
+}
+
+// Importers returns the files which import a file directly or transitively.
+// Files which import it directly are returned first.
+func (ii *ImportIndex) Importers(path string) []string {
+	ii.mu.RLock()
+	defer ii.mu.RUnlock()
+
+	path = filepath.Clean(path)
+	seen := map[string]bool{path: true}
+
+	var out []string
+	level := []string{path}
+	for len(level) > 0 {
+		var next []string
+		for _, cur := range level {
+			for importer := range ii.importers[cur] {
+				if seen[importer] {
+					continue
+				}
+
+				seen[importer] = true
+				next = append(next, importer)
+			}
+		}
+
+		sort.Strings(next)
+		out = append(out, next...)
+		level = next
+	}
+
+	return out
+}
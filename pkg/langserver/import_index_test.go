@@ -0,0 +1,52 @@
+package langserver
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestImportIndex(t *testing.T) {
+	dir, err := ioutil.TempDir("", "")
+	require.NoError(t, err)
+	defer os.RemoveAll(dir)
+
+	files := map[string]string{
+		"vendor/k/k.libsonnet": "{}",
+		"lib/app.libsonnet":    "local k = import 'k.libsonnet';\n{k: k}",
+		"a.jsonnet":            "import 'lib/app.libsonnet'",
+		"b.jsonnet":            "local k = import 'k.libsonnet';\nlocal missing = import 'missing.libsonnet';\nk",
+		".hidden/c.jsonnet":    "import '../vendor/k/k.libsonnet'",
+	}
+
+	for name, source := range files {
+		path := filepath.Join(dir, name)
+		require.NoError(t, os.MkdirAll(filepath.Dir(path), 0700))
+		require.NoError(t, ioutil.WriteFile(path, []byte(source), 0600))
+	}
+
+	libPaths := []string{filepath.Join(dir, "vendor/k")}
+
+	ii := NewImportIndex()
+	require.NoError(t, ii.AddDir(dir, libPaths))
+
+	path := func(name string) string {
+		return filepath.Join(dir, name)
+	}
+
+	k := path("vendor/k/k.libsonnet")
+
+	expected := []string{path("b.jsonnet"), path("lib/app.libsonnet"), path("a.jsonnet")}
+	assert.Equal(t, expected, ii.Importers(k))
+	assert.Empty(t, ii.Importers(path("a.jsonnet")))
+
+	require.NoError(t, ii.UpdateSource(path("b.jsonnet"), "{}", libPaths))
+	assert.Equal(t, []string{path("lib/app.libsonnet"), path("a.jsonnet")}, ii.Importers(k))
+
+	ii.Remove(path("lib/app.libsonnet"))
+	assert.Empty(t, ii.Importers(k))
+}
@@ -2,8 +2,10 @@ package langserver
 
 import (
 	"io/ioutil"
+	"os"
 	"path/filepath"
 	"sort"
+	"strings"
 )
 
 // LibPaths manage jsonnet lib paths.
@@ -51,6 +53,60 @@ func (lp *LibPaths) Files() ([]string, error) {
 	return files, nil
 }
 
+// ImportCandidate is a file or directory which can be imported.
+type ImportCandidate struct {
+	// Name is the name of the file or directory.
+	Name string
+	// IsDir is true if the candidate is a directory.
+	IsDir bool
+	// Path is the search path the candidate was found in.
+	Path string
+}
+
+// Candidates returns files and directories in a directory relative to each
+// search path. If a name exists in multiple search paths, the first search
+// path wins, since it is the path an import would resolve to. Files are
+// included if the filter returns true.
+func (lp *LibPaths) Candidates(dir string, filter func(name string) bool) ([]ImportCandidate, error) {
+	seen := make(map[string]bool)
+	var candidates []ImportCandidate
+
+	for _, path := range lp.paths {
+		fis, err := ioutil.ReadDir(filepath.Join(path, dir))
+		if err != nil {
+			if os.IsNotExist(err) {
+				continue
+			}
+			return nil, err
+		}
+
+		for _, fi := range fis {
+			name := fi.Name()
+			if strings.HasPrefix(name, ".") || seen[name] {
+				continue
+			}
+
+			if !fi.IsDir() && !filter(name) {
+				continue
+			}
+
+			seen[name] = true
+			candidates = append(candidates, ImportCandidate{
+				Name:  name,
+				IsDir: fi.IsDir(),
+				Path:  path,
+			})
+		}
+	}
+
+	return candidates, nil
+}
+
+// IsImportable returns true if a file can be imported with `import`.
+func IsImportable(name string) bool {
+	return isJsonnetFile(name) || filepath.Ext(name) == ".json"
+}
+
 func isJsonnetFile(name string) bool {
 	if ext := filepath.Ext(name); ext == ".jsonnet" || ext == ".libsonnet" {
 		return true
@@ -49,3 +49,79 @@ func createFile(t *testing.T, base, path string) {
 	err = ioutil.WriteFile(file, []byte(""), 0600)
 	require.NoError(t, err)
 }
+
+func Test_LibPaths_Candidates(t *testing.T) {
+	dir, err := ioutil.TempDir("", "")
+	require.NoError(t, err)
+	defer os.RemoveAll(dir)
+
+	paths := []string{
+		"path1/lib/file1.libsonnet",
+		"path1/lib/nested/file2.libsonnet",
+		"path1/lib/data.txt",
+		"path2/lib/file1.libsonnet",
+		"path2/lib/file3.json",
+		"path2/other/file4.jsonnet",
+	}
+
+	for _, path := range paths {
+		createFile(t, dir, path)
+	}
+
+	path1 := filepath.Join(dir, "path1")
+	path2 := filepath.Join(dir, "path2")
+
+	cases := []struct {
+		name     string
+		dir      string
+		filter   func(string) bool
+		expected []ImportCandidate
+	}{
+		{
+			name:   "importable files",
+			dir:    "lib",
+			filter: IsImportable,
+			expected: []ImportCandidate{
+				{Name: "file1.libsonnet", Path: path1},
+				{Name: "nested", IsDir: true, Path: path1},
+				{Name: "file3.json", Path: path2},
+			},
+		},
+		{
+			name:   "all files",
+			dir:    "lib",
+			filter: func(string) bool { return true },
+			expected: []ImportCandidate{
+				{Name: "data.txt", Path: path1},
+				{Name: "file1.libsonnet", Path: path1},
+				{Name: "nested", IsDir: true, Path: path1},
+				{Name: "file3.json", Path: path2},
+			},
+		},
+		{
+			name:   "root",
+			dir:    "",
+			filter: IsImportable,
+			expected: []ImportCandidate{
+				{Name: "lib", IsDir: true, Path: path1},
+				{Name: "other", IsDir: true, Path: path2},
+			},
+		},
+		{
+			name:   "missing directory",
+			dir:    "missing",
+			filter: IsImportable,
+		},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			lp := NewLibPaths([]string{path1, path2})
+
+			got, err := lp.Candidates(tc.dir, tc.filter)
+			require.NoError(t, err)
+
+			assert.Equal(t, tc.expected, got)
+		})
+	}
+}
@@ -0,0 +1,92 @@
+package langserver
+
+import (
+	"strconv"
+	"sync"
+
+	"github.com/tminor/jsonnet-language-server/pkg/lsp"
+)
+
+type semanticTokensResult struct {
+	id   string
+	data []int
+}
+
+// SemanticTokensCache stores the semantic tokens last sent for each
+// document, so later requests can be answered with edits.
+type SemanticTokensCache struct {
+	mu      sync.Mutex
+	next    int
+	results map[string]semanticTokensResult
+}
+
+// NewSemanticTokensCache creates an instance of SemanticTokensCache.
+func NewSemanticTokensCache() *SemanticTokensCache {
+	return &SemanticTokensCache{
+		results: make(map[string]semanticTokensResult),
+	}
+}
+
+// Store stores the tokens sent for a document and returns their result id.
+func (c *SemanticTokensCache) Store(uri string, data []int) string {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	c.next++
+	id := strconv.Itoa(c.next)
+	c.results[uri] = semanticTokensResult{id: id, data: data}
+
+	return id
+}
+
+// Get returns the tokens sent for a document if they have the result id.
+func (c *SemanticTokensCache) Get(uri, resultID string) ([]int, bool) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	result, ok := c.results[uri]
+	if !ok || result.id != resultID {
+		return nil, false
+	}
+
+	return result.data, true
+}
+
+// Remove removes the tokens stored for a document.
+func (c *SemanticTokensCache) Remove(uri string) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	delete(c.results, uri)
+}
+
+// SemanticTokensEdits returns the edits which change previous tokens to
+// current tokens. Tokens before and after the change are kept, so there is
+// at most one edit.
+func SemanticTokensEdits(previous, current []int) []lsp.SemanticTokensEdit {
+	start := 0
+	for start < len(previous) && start < len(current) && previous[start] == current[start] {
+		start++
+	}
+
+	end := 0
+	for end < len(previous)-start && end < len(current)-start &&
+		previous[len(previous)-1-end] == current[len(current)-1-end] {
+		end++
+	}
+
+	deleteCount := len(previous) - start - end
+	data := current[start : len(current)-end]
+
+	if deleteCount == 0 && len(data) == 0 {
+		return []lsp.SemanticTokensEdit{}
+	}
+
+	return []lsp.SemanticTokensEdit{
+		{
+			Start:       start,
+			DeleteCount: deleteCount,
+			Data:        data,
+		},
+	}
+}
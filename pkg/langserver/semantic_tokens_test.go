@@ -0,0 +1,75 @@
+package langserver
+
+import (
+	"testing"
+
+	"github.com/tminor/jsonnet-language-server/pkg/lsp"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestSemanticTokensCache(t *testing.T) {
+	c := NewSemanticTokensCache()
+
+	id := c.Store("file:///a", []int{1, 2, 3})
+
+	data, ok := c.Get("file:///a", id)
+	require.True(t, ok)
+	assert.Equal(t, []int{1, 2, 3}, data)
+
+	newID := c.Store("file:///a", []int{4})
+	assert.NotEqual(t, id, newID)
+
+	_, ok = c.Get("file:///a", id)
+	assert.False(t, ok)
+
+	c.Remove("file:///a")
+	_, ok = c.Get("file:///a", newID)
+	assert.False(t, ok)
+}
+
+func TestSemanticTokensEdits(t *testing.T) {
+	cases := []struct {
+		name     string
+		previous []int
+		current  []int
+		expected []lsp.SemanticTokensEdit
+	}{
+		{
+			name:     "unchanged",
+			previous: []int{1, 2, 3},
+			current:  []int{1, 2, 3},
+			expected: []lsp.SemanticTokensEdit{},
+		},
+		{
+			name:     "insert",
+			previous: []int{1, 2, 3},
+			current:  []int{1, 2, 4, 5, 3},
+			expected: []lsp.SemanticTokensEdit{{Start: 2, DeleteCount: 0, Data: []int{4, 5}}},
+		},
+		{
+			name:     "delete",
+			previous: []int{1, 2, 3, 4},
+			current:  []int{1, 4},
+			expected: []lsp.SemanticTokensEdit{{Start: 1, DeleteCount: 2, Data: []int{}}},
+		},
+		{
+			name:     "replace",
+			previous: []int{1, 2, 3},
+			current:  []int{1, 5, 3},
+			expected: []lsp.SemanticTokensEdit{{Start: 1, DeleteCount: 1, Data: []int{5}}},
+		},
+		{
+			name:     "repeated values",
+			previous: []int{1, 1},
+			current:  []int{1, 1, 1},
+			expected: []lsp.SemanticTokensEdit{{Start: 2, DeleteCount: 0, Data: []int{1}}},
+		},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			assert.Equal(t, tc.expected, SemanticTokensEdits(tc.previous, tc.current))
+		})
+	}
+}
@@ -0,0 +1,141 @@
+package langserver
+
+import (
+	"bytes"
+	"strings"
+
+	"github.com/pkg/errors"
+)
+
+// Snippet is a template for a Jsonnet construct. The body uses the LSP
+// snippet syntax, e.g. `local ${1:name} = ${0};`.
+type Snippet struct {
+	Label  string
+	Detail string
+	Body   string
+}
+
+// DefaultSnippets are snippets for common Jsonnet constructs.
+var DefaultSnippets = []Snippet{
+	{
+		Label:  "local function",
+		Detail: "local function definition",
+		Body:   "local ${1:name}(${2:params}) =\n  ${0:body};",
+	},
+	{
+		Label:  "function",
+		Detail: "anonymous function",
+		Body:   "function(${1:params}) ${0:body}",
+	},
+	{
+		Label:  "if then else",
+		Detail: "conditional expression",
+		Body:   "if ${1:condition} then ${2:trueValue} else ${0:falseValue}",
+	},
+	{
+		Label:  "array comprehension",
+		Detail: "[expr for x in arr]",
+		Body:   "[${1:expr} for ${2:x} in ${0:arr}]",
+	},
+	{
+		Label:  "object comprehension",
+		Detail: "{[key]: value for x in arr}",
+		Body:   "{\n  [${1:key}]: ${2:value}\n  for ${3:x} in ${0:arr}\n}",
+	},
+	{
+		Label:  "std.foldl",
+		Detail: "fold an array from the left",
+		Body:   "std.foldl(function(${1:acc}, ${2:x}) ${3:acc}, ${4:arr}, ${0:init})",
+	},
+	{
+		Label:  "assert",
+		Detail: "assertion with message",
+		Body:   "assert ${1:condition} : ${2:'message'};",
+	},
+	{
+		Label:  "text block",
+		Detail: "||| text block |||",
+		Body:   "|||\n  ${0:text}\n|||",
+	},
+}
+
+// MergeSnippets merges snippets into a set of base snippets. Snippets with
+// the same label replace the base snippet.
+func MergeSnippets(base []Snippet, snippets []Snippet) []Snippet {
+	out := make([]Snippet, len(base))
+	copy(out, base)
+
+	for _, snippet := range snippets {
+		replaced := false
+		for i := range out {
+			if out[i].Label == snippet.Label {
+				out[i] = snippet
+				replaced = true
+				break
+			}
+		}
+
+		if !replaced {
+			out = append(out, snippet)
+		}
+	}
+
+	return out
+}
+
+// Validate validates a snippet.
+func (s Snippet) Validate() error {
+	if s.Label == "" {
+		return errors.New("snippet label is blank")
+	}
+
+	if s.Body == "" {
+		return errors.Errorf("snippet %q body is blank", s.Label)
+	}
+
+	return nil
+}
+
+// PlainText converts the snippet body to plain text for clients without
+// snippet support. Placeholders are replaced with their default text and
+// tab stops are removed.
+func (s Snippet) PlainText() string {
+	var buf bytes.Buffer
+
+	body := s.Body
+	for i := 0; i < len(body); i++ {
+		c := body[i]
+
+		switch {
+		case c == '\\' && i+1 < len(body) && strings.ContainsRune(`$}\`, rune(body[i+1])):
+			i++
+			buf.WriteByte(body[i])
+		case c == '$' && i+1 < len(body) && body[i+1] == '{':
+			// ${n:default} or ${n}
+			end := strings.IndexByte(body[i:], '}')
+			if end < 0 {
+				buf.WriteString(body[i:])
+				return buf.String()
+			}
+
+			placeholder := body[i+2 : i+end]
+			if colon := strings.IndexByte(placeholder, ':'); colon >= 0 {
+				buf.WriteString(placeholder[colon+1:])
+			}
+			i += end
+		case c == '$' && i+1 < len(body) && isDigit(body[i+1]):
+			// $n
+			for i+1 < len(body) && isDigit(body[i+1]) {
+				i++
+			}
+		default:
+			buf.WriteByte(c)
+		}
+	}
+
+	return buf.String()
+}
+
+func isDigit(c byte) bool {
+	return c >= '0' && c <= '9'
+}
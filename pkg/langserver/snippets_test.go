@@ -0,0 +1,69 @@
+package langserver
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestSnippet_PlainText(t *testing.T) {
+	cases := []struct {
+		name     string
+		body     string
+		expected string
+	}{
+		{
+			name:     "placeholders",
+			body:     "local ${1:name} = ${0:value};",
+			expected: "local name = value;",
+		},
+		{
+			name:     "tab stops",
+			body:     "[$1 for x in $0]",
+			expected: "[ for x in ]",
+		},
+		{
+			name:     "empty placeholder",
+			body:     "f(${1})",
+			expected: "f()",
+		},
+		{
+			name:     "escaped characters",
+			body:     `"\${x\}"`,
+			expected: `"${x}"`,
+		},
+		{
+			name:     "no placeholders",
+			body:     "std.length(x)",
+			expected: "std.length(x)",
+		},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			s := Snippet{Label: tc.name, Body: tc.body}
+			assert.Equal(t, tc.expected, s.PlainText())
+		})
+	}
+}
+
+func TestMergeSnippets(t *testing.T) {
+	base := []Snippet{
+		{Label: "a", Body: "a"},
+		{Label: "b", Body: "b"},
+	}
+
+	got := MergeSnippets(base, []Snippet{
+		{Label: "b", Body: "new b"},
+		{Label: "c", Body: "c"},
+	})
+
+	expected := []Snippet{
+		{Label: "a", Body: "a"},
+		{Label: "b", Body: "new b"},
+		{Label: "c", Body: "c"},
+	}
+
+	assert.Equal(t, expected, got)
+	assert.Equal(t, "b", base[1].Body)
+}
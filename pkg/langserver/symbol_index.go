@@ -0,0 +1,163 @@
+package langserver
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"sync"
+
+	"github.com/tminor/jsonnet-language-server/pkg/analysis/lexical/token"
+	"github.com/pkg/errors"
+)
+
+// SymbolIndex indexes the exported symbols of Jsonnet files so they can be
+// searched across the workspace. Files are indexed individually, so the
+// index can be updated as files change.
+type SymbolIndex struct {
+	mu    sync.RWMutex
+	files map[string][]token.WorkspaceSymbol
+}
+
+// NewSymbolIndex creates an instance of SymbolIndex.
+func NewSymbolIndex() *SymbolIndex {
+	return &SymbolIndex{
+		files: make(map[string][]token.WorkspaceSymbol),
+	}
+}
+
+// AddDir indexes the Jsonnet files in a directory and its subdirectories.
+// Hidden directories are skipped. Files which can't be parsed are skipped.
+// Entries which can't be read are skipped, and are reported in the error
+// after the rest of the directory is indexed.
+func (si *SymbolIndex) AddDir(dir string) error {
+	return walkJsonnetFiles(dir, func(path string) {
+		_ = si.Update(path)
+	})
+}
+
+// walkJsonnetFiles calls fn for each Jsonnet file in a directory and its
+// subdirectories. Hidden directories are skipped. An entry which can't be
+// read doesn't stop the walk. The entries which were skipped are returned
+// as an error.
+func walkJsonnetFiles(dir string, fn func(path string)) error {
+	var skipped []string
+
+	err := filepath.Walk(dir, func(path string, fi os.FileInfo, err error) error {
+		if err != nil {
+			skipped = append(skipped, err.Error())
+			if fi != nil && fi.IsDir() {
+				return filepath.SkipDir
+			}
+
+			return nil
+		}
+
+		if fi.IsDir() {
+			if path != dir && strings.HasPrefix(fi.Name(), ".") {
+				return filepath.SkipDir
+			}
+
+			return nil
+		}
+
+		if isJsonnetFile(path) {
+			fn(path)
+		}
+
+		return nil
+	})
+	if err != nil {
+		return err
+	}
+
+	if len(skipped) > 0 {
+		return errors.Errorf("skipped entries in %s: %s", dir, strings.Join(skipped, "; "))
+	}
+
+	return nil
+}
+
+// Update reads a file from disk and indexes it.
+func (si *SymbolIndex) Update(path string) error {
+	/* #nosec */
+	data, err := ioutil.ReadFile(path)
+	if err != nil {
+		return err
+	}
+
+	return si.UpdateSource(path, string(data))
+}
+
+// UpdateSource indexes a file's source.
+func (si *SymbolIndex) UpdateSource(path, source string) error {
+	symbols, err := token.ExportedSymbols(path, source)
+	if err != nil {
+		return err
+	}
+
+	si.mu.Lock()
+	defer si.mu.Unlock()
+
+	si.files[path] = symbols
+
+	return nil
+}
+
+// Remove removes a file from the index.
+func (si *SymbolIndex) Remove(path string) {
+	si.mu.Lock()
+	defer si.mu.Unlock()
+
+	delete(si.files, path)
+}
+
+type symbolMatch struct {
+	symbol token.WorkspaceSymbol
+	score  int
+}
+
+// Search finds symbols whose qualified name fuzzy matches a query. The best
+// matches are returned first. If limit is greater than 0, at most limit
+// symbols are returned.
+func (si *SymbolIndex) Search(query string, limit int) []token.WorkspaceSymbol {
+	si.mu.RLock()
+	defer si.mu.RUnlock()
+
+	var matches []symbolMatch
+	for _, symbols := range si.files {
+		for i := range symbols {
+			score, ok := FuzzyMatch(query, symbols[i].QualifiedName())
+			if !ok {
+				continue
+			}
+
+			matches = append(matches, symbolMatch{symbol: symbols[i], score: score})
+		}
+	}
+
+	sort.Slice(matches, func(i, j int) bool {
+		if matches[i].score != matches[j].score {
+			return matches[i].score > matches[j].score
+		}
+
+		a, b := matches[i].symbol, matches[j].symbol
+		if a.QualifiedName() != b.QualifiedName() {
+			return a.QualifiedName() < b.QualifiedName()
+		}
+
+		return a.Location.URI() < b.Location.URI()
+	})
+
+	if limit > 0 && len(matches) > limit {
+		matches = matches[:limit]
+	}
+
+	out := make([]token.WorkspaceSymbol, 0, len(matches))
+	for _, m := range matches {
+		out = append(out, m.symbol)
+	}
+
+	return out
+}
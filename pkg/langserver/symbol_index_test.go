@@ -0,0 +1,57 @@
+package langserver
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/tminor/jsonnet-language-server/pkg/analysis/lexical/token"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestSymbolIndex(t *testing.T) {
+	dir, err := ioutil.TempDir("", "")
+	require.NoError(t, err)
+	defer os.RemoveAll(dir)
+
+	files := map[string]string{
+		"vendor/k/k.libsonnet":   "{apps:: {v1:: {deployment:: {new(name):: {}}}}}",
+		"app.jsonnet":            "local k = import 'k.libsonnet';\n{deploy: k.apps.v1.deployment.new('app')}",
+		".hidden/skip.libsonnet": "{deploymentNew: 1}",
+		"invalid.jsonnet":        "{",
+	}
+
+	for name, source := range files {
+		path := filepath.Join(dir, name)
+		require.NoError(t, os.MkdirAll(filepath.Dir(path), 0700))
+		require.NoError(t, ioutil.WriteFile(path, []byte(source), 0600))
+	}
+
+	si := NewSymbolIndex()
+	require.NoError(t, si.AddDir(dir))
+
+	names := func(symbols []token.WorkspaceSymbol) []string {
+		var out []string
+		for i := range symbols {
+			out = append(out, symbols[i].QualifiedName())
+		}
+		return out
+	}
+
+	symbols := si.Search("deployment.new", 0)
+	assert.Equal(t, []string{"apps.v1.deployment.new"}, names(symbols))
+	assert.Equal(t, filepath.Join(dir, "vendor/k/k.libsonnet"), symbols[0].Location.URI())
+
+	assert.Len(t, si.Search("", 0), 6)
+	assert.Len(t, si.Search("", 2), 2)
+
+	path := filepath.Join(dir, "app.jsonnet")
+	require.NoError(t, si.UpdateSource(path, "{service: 1}"))
+	assert.Equal(t, []string{"service"}, names(si.Search("service", 0)))
+	assert.Empty(t, si.Search("deploy:", 0))
+
+	si.Remove(path)
+	assert.Empty(t, si.Search("service", 0))
+}
@@ -20,6 +20,25 @@ type ClientCapabilities struct {
 	// XContentProvider indicates the client provides support for
 	// textDocument/xcontent. This is a Sourcegraph extension.
 	XContentProvider bool `json:"xcontentProvider,omitempty"`
+
+	TextDocument TextDocumentClientCapabilities `json:"textDocument,omitempty"`
+}
+
+type TextDocumentClientCapabilities struct {
+	Completion CompletionClientCapabilities `json:"completion,omitempty"`
+	Hover      HoverClientCapabilities      `json:"hover,omitempty"`
+}
+
+type HoverClientCapabilities struct {
+	ContentFormat []MarkupKind `json:"contentFormat,omitempty"`
+}
+
+type CompletionClientCapabilities struct {
+	CompletionItem CompletionItemClientCapabilities `json:"completionItem,omitempty"`
+}
+
+type CompletionItemClientCapabilities struct {
+	SnippetSupport bool `json:"snippetSupport,omitempty"`
 }
 
 type InitializeResult struct {
@@ -41,6 +60,7 @@ const (
 type ServerCapabilities struct {
 	TextDocumentSync                 int                              `json:"textDocumentSync,omitempty"`
 	HoverProvider                    bool                             `json:"hoverProvider,omitempty"`
+	ImplementationProvider           bool                             `json:"implementationProvider,omitempty"`
 	CompletionProvider               *CompletionOptions               `json:"completionProvider,omitempty"`
 	SignatureHelpProvider            *SignatureHelpOptions            `json:"signatureHelpProvider,omitempty"`
 	DefinitionProvider               bool                             `json:"definitionProvider,omitempty"`
@@ -54,6 +74,14 @@ type ServerCapabilities struct {
 	DocumentRangeFormattingProvider  bool                             `json:"documentRangeFormattingProvider,omitempty"`
 	DocumentOnTypeFormattingProvider *DocumentOnTypeFormattingOptions `json:"documentOnTypeFormattingProvider,omitempty"`
 	RenameProvider                   bool                             `json:"renameProvider,omitempty"`
+	ExecuteCommandProvider           *ExecuteCommandOptions           `json:"executeCommandProvider,omitempty"`
+	InlayHintProvider                bool                             `json:"inlayHintProvider,omitempty"`
+	SemanticTokensProvider           *SemanticTokensOptions           `json:"semanticTokensProvider,omitempty"`
+	FoldingRangeProvider             bool                             `json:"foldingRangeProvider,omitempty"`
+	SelectionRangeProvider           bool                             `json:"selectionRangeProvider,omitempty"`
+	DocumentLinkProvider             *DocumentLinkOptions             `json:"documentLinkProvider,omitempty"`
+	CallHierarchyProvider            bool                             `json:"callHierarchyProvider,omitempty"`
+	TypeHierarchyProvider            bool                             `json:"typeHierarchyProvider,omitempty"`
 }
 
 type CompletionOptions struct {
@@ -61,6 +89,15 @@ type CompletionOptions struct {
 	TriggerCharacters []string `json:"triggerCharacters,omitempty"`
 }
 
+type ExecuteCommandOptions struct {
+	Commands []string `json:"commands"`
+}
+
+type ExecuteCommandParams struct {
+	Command   string        `json:"command"`
+	Arguments []interface{} `json:"arguments,omitempty"`
+}
+
 type DocumentOnTypeFormattingOptions struct {
 	FirstTriggerCharacter string   `json:"firstTriggerCharacter"`
 	MoreTriggerCharacter  []string `json:"moreTriggerCharacter,omitempty"`
@@ -70,6 +107,11 @@ type CodeLensOptions struct {
 	ResolveProvider bool `json:"resolveProvider,omitempty"`
 }
 
+// DocumentLinkOptions are options for document links.
+type DocumentLinkOptions struct {
+	ResolveProvider bool `json:"resolveProvider,omitempty"`
+}
+
 type SignatureHelpOptions struct {
 	TriggerCharacters []string `json:"triggerCharacters,omitempty"`
 }
@@ -95,18 +137,28 @@ const (
 	CIKColor                          = 16
 	CIKFile                           = 17
 	CIKReference                      = 18
+	CIKFolder                         = 19
+)
+
+type InsertTextFormat int
+
+const (
+	ITFPlainText InsertTextFormat = 1
+	ITFSnippet   InsertTextFormat = 2
 )
 
 type CompletionItem struct {
-	Label         string      `json:"label"`
-	Kind          int         `json:"kind,omitempty"`
-	Detail        string      `json:"detail,omitempty"`
-	Documentation string      `json:"documentation,omitempty"`
-	SortText      string      `json:"sortText,omitempty"`
-	FilterText    string      `json:"filterText,omitempty"`
-	InsertText    string      `json:"insertText,omitempty"`
-	TextEdit      TextEdit    `json:"textEdit,omitempty"`
-	Data          interface{} `json:"data,omitempty"`
+	Label            string           `json:"label"`
+	Kind             int              `json:"kind,omitempty"`
+	Detail           string           `json:"detail,omitempty"`
+	Documentation    string           `json:"documentation,omitempty"`
+	SortText         string           `json:"sortText,omitempty"`
+	FilterText       string           `json:"filterText,omitempty"`
+	InsertText       string           `json:"insertText,omitempty"`
+	InsertTextFormat InsertTextFormat `json:"insertTextFormat,omitempty"`
+	TextEdit         TextEdit         `json:"textEdit,omitempty"`
+	Command          *Command         `json:"command,omitempty"`
+	Data             interface{}      `json:"data,omitempty"`
 }
 
 type CompletionList struct {
@@ -115,8 +167,21 @@ type CompletionList struct {
 }
 
 type Hover struct {
-	Contents []MarkedString `json:"contents,omitempty"`
-	Range    Range          `json:"range"`
+	// Contents is either []MarkedString or MarkupContent.
+	Contents interface{} `json:"contents,omitempty"`
+	Range    Range       `json:"range"`
+}
+
+type MarkupKind string
+
+const (
+	MKPlainText MarkupKind = "plaintext"
+	MKMarkdown  MarkupKind = "markdown"
+)
+
+type MarkupContent struct {
+	Kind  MarkupKind `json:"kind"`
+	Value string     `json:"value"`
 }
 
 type MarkedString struct {
@@ -147,9 +212,23 @@ type ReferenceContext struct {
 
 type ReferenceParams struct {
 	TextDocumentPositionParams
+	PartialResultParams
 	Context ReferenceContext `json:"context"`
 }
 
+// PartialResultParams is embedded in the params of requests whose results
+// can be streamed to the client.
+type PartialResultParams struct {
+	PartialResultToken interface{} `json:"partialResultToken,omitempty"`
+}
+
+// ProgressParams are the params of a $/progress notification. Partial
+// results are reported as the value.
+type ProgressParams struct {
+	Token interface{} `json:"token"`
+	Value interface{} `json:"value"`
+}
+
 type DocumentHighlightKind int
 
 const (
@@ -225,6 +304,23 @@ type CodeActionParams struct {
 	Context      CodeActionContext      `json:"context"`
 }
 
+// CodeActionKind is the kind of a code action.
+type CodeActionKind string
+
+const (
+	// CAKQuickFix is the kind of code actions which fix diagnostics.
+	CAKQuickFix CodeActionKind = "quickfix"
+)
+
+// CodeAction is a change which can be applied to a document.
+type CodeAction struct {
+	Title       string         `json:"title"`
+	Kind        CodeActionKind `json:"kind,omitempty"`
+	Diagnostics []Diagnostic   `json:"diagnostics,omitempty"`
+	IsPreferred bool           `json:"isPreferred,omitempty"`
+	Edit        *WorkspaceEdit `json:"edit,omitempty"`
+}
+
 type CodeLensParams struct {
 	TextDocument TextDocumentIdentifier `json:"textDocument"`
 }
@@ -375,3 +471,193 @@ type Registration struct {
 type RegistrationParams struct {
 	Registrations []Registration `json:"registrations,omitempty"`
 }
+
+// InlayHintParams are the parameters for an inlay hint request.
+type InlayHintParams struct {
+	TextDocument TextDocumentIdentifier `json:"textDocument"`
+	Range        Range                  `json:"range"`
+}
+
+// InlayHint is a hint shown inline with source.
+type InlayHint struct {
+	Position     Position `json:"position"`
+	Label        string   `json:"label"`
+	Kind         int      `json:"kind,omitempty"`
+	PaddingLeft  bool     `json:"paddingLeft,omitempty"`
+	PaddingRight bool     `json:"paddingRight,omitempty"`
+}
+
+const (
+	// IHKType is an inlay hint for a type.
+	IHKType int = 1
+	// IHKParameter is an inlay hint for a parameter.
+	IHKParameter int = 2
+)
+
+// SemanticTokensLegend are the token types and modifiers a server uses.
+type SemanticTokensLegend struct {
+	TokenTypes     []string `json:"tokenTypes"`
+	TokenModifiers []string `json:"tokenModifiers"`
+}
+
+// SemanticTokensOptions are the semantic token requests a server supports.
+type SemanticTokensOptions struct {
+	Legend SemanticTokensLegend       `json:"legend"`
+	Range  bool                       `json:"range,omitempty"`
+	Full   *SemanticTokensFullOptions `json:"full,omitempty"`
+}
+
+// SemanticTokensFullOptions are options for full document semantic tokens.
+type SemanticTokensFullOptions struct {
+	Delta bool `json:"delta,omitempty"`
+}
+
+// SemanticTokensParams are the parameters for a semantic tokens request.
+type SemanticTokensParams struct {
+	TextDocument TextDocumentIdentifier `json:"textDocument"`
+}
+
+// SemanticTokensRangeParams are the parameters for a semantic tokens range
+// request.
+type SemanticTokensRangeParams struct {
+	TextDocument TextDocumentIdentifier `json:"textDocument"`
+	Range        Range                  `json:"range"`
+}
+
+// SemanticTokensDeltaParams are the parameters for a semantic tokens delta
+// request.
+type SemanticTokensDeltaParams struct {
+	TextDocument     TextDocumentIdentifier `json:"textDocument"`
+	PreviousResultID string                 `json:"previousResultId"`
+}
+
+// SemanticTokens are encoded semantic tokens.
+type SemanticTokens struct {
+	ResultID string `json:"resultId,omitempty"`
+	Data     []int  `json:"data"`
+}
+
+// SemanticTokensDelta are edits to previously sent semantic tokens.
+type SemanticTokensDelta struct {
+	ResultID string               `json:"resultId,omitempty"`
+	Edits    []SemanticTokensEdit `json:"edits"`
+}
+
+// SemanticTokensEdit is an edit to encoded semantic tokens.
+type SemanticTokensEdit struct {
+	Start       int   `json:"start"`
+	DeleteCount int   `json:"deleteCount"`
+	Data        []int `json:"data,omitempty"`
+}
+
+// FoldingRangeParams are the parameters for a folding range request.
+type FoldingRangeParams struct {
+	TextDocument TextDocumentIdentifier `json:"textDocument"`
+}
+
+// FoldingRange is a range of lines which can be folded. Lines start at 0.
+type FoldingRange struct {
+	StartLine int    `json:"startLine"`
+	EndLine   int    `json:"endLine"`
+	Kind      string `json:"kind,omitempty"`
+}
+
+const (
+	// FRKComment folds a comment.
+	FRKComment = "comment"
+	// FRKImports folds imports.
+	FRKImports = "imports"
+	// FRKRegion folds a region.
+	FRKRegion = "region"
+)
+
+// SelectionRangeParams are the parameters for a selection range request.
+type SelectionRangeParams struct {
+	TextDocument TextDocumentIdentifier `json:"textDocument"`
+	Positions    []Position             `json:"positions"`
+}
+
+// SelectionRange is a range a selection can be expanded to. Parent is the
+// range enclosing this range.
+type SelectionRange struct {
+	Range  Range           `json:"range"`
+	Parent *SelectionRange `json:"parent,omitempty"`
+}
+
+// DocumentLinkParams are the parameters for a document link request.
+type DocumentLinkParams struct {
+	TextDocument TextDocumentIdentifier `json:"textDocument"`
+}
+
+// DocumentLink is a range in a document which links to a URI.
+type DocumentLink struct {
+	Range   Range  `json:"range"`
+	Target  string `json:"target,omitempty"`
+	Tooltip string `json:"tooltip,omitempty"`
+}
+
+// VirtualDocument is a document created by the server which doesn't exist
+// on disk, e.g. the result of evaluating a file.
+type VirtualDocument struct {
+	URI        string `json:"uri"`
+	LanguageID string `json:"languageId"`
+	Text       string `json:"text"`
+}
+
+// CallHierarchyItem is a function in a call hierarchy.
+type CallHierarchyItem struct {
+	Name           string     `json:"name"`
+	Kind           SymbolKind `json:"kind"`
+	Detail         string     `json:"detail,omitempty"`
+	URI            string     `json:"uri"`
+	Range          Range      `json:"range"`
+	SelectionRange Range      `json:"selectionRange"`
+}
+
+// CallHierarchyIncomingCallsParams are the parameters for a
+// callHierarchy/incomingCalls request.
+type CallHierarchyIncomingCallsParams struct {
+	Item CallHierarchyItem `json:"item"`
+}
+
+// CallHierarchyIncomingCall is a call to an item. FromRanges are the ranges
+// of the calls in the caller.
+type CallHierarchyIncomingCall struct {
+	From       CallHierarchyItem `json:"from"`
+	FromRanges []Range           `json:"fromRanges"`
+}
+
+// CallHierarchyOutgoingCallsParams are the parameters for a
+// callHierarchy/outgoingCalls request.
+type CallHierarchyOutgoingCallsParams struct {
+	Item CallHierarchyItem `json:"item"`
+}
+
+// CallHierarchyOutgoingCall is a call made by an item. FromRanges are the
+// ranges of the calls in the item calling To.
+type CallHierarchyOutgoingCall struct {
+	To         CallHierarchyItem `json:"to"`
+	FromRanges []Range           `json:"fromRanges"`
+}
+
+// TypeHierarchyItem is an object in a type hierarchy.
+type TypeHierarchyItem struct {
+	Name           string     `json:"name"`
+	Kind           SymbolKind `json:"kind"`
+	Detail         string     `json:"detail,omitempty"`
+	URI            string     `json:"uri"`
+	Range          Range      `json:"range"`
+	SelectionRange Range      `json:"selectionRange"`
+}
+
+// TypeHierarchySupertypesParams are the parameters for a
+// typeHierarchy/supertypes request.
+type TypeHierarchySupertypesParams struct {
+	Item TypeHierarchyItem `json:"item"`
+}
+
+// TypeHierarchySubtypesParams are the parameters for a
+// typeHierarchy/subtypes request.
+type TypeHierarchySubtypesParams struct {
+	Item TypeHierarchyItem `json:"item"`
+}
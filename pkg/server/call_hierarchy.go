@@ -0,0 +1,161 @@
+package server
+
+import (
+	"context"
+
+	"github.com/tminor/jsonnet-language-server/pkg/analysis/lexical/token"
+	"github.com/tminor/jsonnet-language-server/pkg/config"
+	"github.com/tminor/jsonnet-language-server/pkg/lsp"
+	jpos "github.com/tminor/jsonnet-language-server/pkg/util/position"
+	"github.com/tminor/jsonnet-language-server/pkg/util/uri"
+	opentracing "github.com/opentracing/opentracing-go"
+	"github.com/opentracing/opentracing-go/log"
+)
+
+func textDocumentPrepareCallHierarchy(ctx context.Context, r *request, c *config.Config) (interface{}, error) {
+	var params lsp.TextDocumentPositionParams
+	if err := r.Decode(&params); err != nil {
+		return nil, err
+	}
+
+	doc, err := c.Text(ctx, params.TextDocument.URI)
+	if err != nil {
+		return nil, err
+	}
+
+	path, err := uri.ToPath(params.TextDocument.URI)
+	if err != nil {
+		return nil, err
+	}
+
+	pos := jpos.FromLSPPosition(params.Position)
+
+	items, err := token.PrepareCallHierarchy(path, doc.String(), pos, c.NodeCache(), c.JsonnetLibPaths())
+	if err != nil {
+		return nil, err
+	}
+
+	out := []lsp.CallHierarchyItem{}
+	for i := range items {
+		out = append(out, callHierarchyItem(items[i]))
+	}
+
+	return out, nil
+}
+
+// callHierarchyIncomingCalls finds the calls to a function in the file it is
+// declared in and the files which import it.
+func callHierarchyIncomingCalls(ctx context.Context, r *request, c *config.Config) (interface{}, error) {
+	span := opentracing.SpanFromContext(ctx)
+
+	var params lsp.CallHierarchyIncomingCallsParams
+	if err := r.Decode(&params); err != nil {
+		return nil, err
+	}
+
+	item, err := fromCallHierarchyItem(params.Item)
+	if err != nil {
+		return nil, err
+	}
+
+	out := []lsp.CallHierarchyIncomingCall{}
+
+	for _, filename := range dependentFiles(c, []jpos.Location{item.Declaration()}) {
+		if err := ctx.Err(); err != nil {
+			return nil, err
+		}
+
+		doc, err := c.Text(ctx, uri.FromPath(filename))
+		if err != nil {
+			span.LogFields(log.Error(err))
+			continue
+		}
+
+		calls, err := token.IncomingCalls(filename, doc.String(), item, c.NodeCache(), c.JsonnetLibPaths())
+		if err != nil {
+			span.LogFields(log.Error(err))
+			continue
+		}
+
+		for i := range calls {
+			out = append(out, lsp.CallHierarchyIncomingCall{
+				From:       callHierarchyItem(calls[i].Item),
+				FromRanges: callRanges(calls[i].Ranges),
+			})
+		}
+	}
+
+	return out, nil
+}
+
+// callHierarchyOutgoingCalls finds the calls made by a function.
+func callHierarchyOutgoingCalls(ctx context.Context, r *request, c *config.Config) (interface{}, error) {
+	var params lsp.CallHierarchyOutgoingCallsParams
+	if err := r.Decode(&params); err != nil {
+		return nil, err
+	}
+
+	item, err := fromCallHierarchyItem(params.Item)
+	if err != nil {
+		return nil, err
+	}
+
+	doc, err := c.Text(ctx, params.Item.URI)
+	if err != nil {
+		return nil, err
+	}
+
+	calls, err := token.OutgoingCalls(item.Location.URI(), doc.String(), item, c.NodeCache(), c.JsonnetLibPaths())
+	if err != nil {
+		return nil, err
+	}
+
+	out := []lsp.CallHierarchyOutgoingCall{}
+	for i := range calls {
+		out = append(out, lsp.CallHierarchyOutgoingCall{
+			To:         callHierarchyItem(calls[i].Item),
+			FromRanges: callRanges(calls[i].Ranges),
+		})
+	}
+
+	return out, nil
+}
+
+func callHierarchyItem(item token.CallHierarchyItem) lsp.CallHierarchyItem {
+	r := item.Location.Range()
+
+	return lsp.CallHierarchyItem{
+		Name:           item.Name,
+		Kind:           item.Kind,
+		Detail:         item.Detail,
+		URI:            uri.FromPath(item.Location.URI()),
+		Range:          r.ToLSP(),
+		SelectionRange: item.SelectionRange.ToLSP(),
+	}
+}
+
+// fromCallHierarchyItem converts an item sent by the client back to an item.
+// Items are identified by their file and selection range.
+func fromCallHierarchyItem(item lsp.CallHierarchyItem) (token.CallHierarchyItem, error) {
+	path, err := uri.ToPath(item.URI)
+	if err != nil {
+		return token.CallHierarchyItem{}, err
+	}
+
+	return token.CallHierarchyItem{
+		Name:           item.Name,
+		Kind:           item.Kind,
+		Detail:         item.Detail,
+		Location:       jpos.NewLocation(path, jpos.FromLSPRange(item.Range)),
+		SelectionRange: jpos.FromLSPRange(item.SelectionRange),
+	}, nil
+}
+
+func callRanges(ranges []jpos.Range) []lsp.Range {
+	out := make([]lsp.Range, 0, len(ranges))
+	for i := range ranges {
+		out = append(out, ranges[i].ToLSP())
+	}
+
+	return out
+}
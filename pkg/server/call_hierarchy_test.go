@@ -0,0 +1,43 @@
+package server
+
+import (
+	"testing"
+
+	"github.com/tminor/jsonnet-language-server/pkg/analysis/lexical/token"
+	"github.com/tminor/jsonnet-language-server/pkg/lsp"
+	jpos "github.com/tminor/jsonnet-language-server/pkg/util/position"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func Test_callHierarchyItem(t *testing.T) {
+	item := token.CallHierarchyItem{
+		Name:           "add",
+		Kind:           lsp.SKFunction,
+		Detail:         "function(a, b)",
+		Location:       jpos.NewLocation("/file.jsonnet", jpos.NewRangeFromCoords(1, 7, 1, 24)),
+		SelectionRange: jpos.NewRangeFromCoords(1, 7, 1, 10),
+	}
+
+	expected := lsp.CallHierarchyItem{
+		Name:   "add",
+		Kind:   lsp.SKFunction,
+		Detail: "function(a, b)",
+		URI:    "file:///file.jsonnet",
+		Range: lsp.Range{
+			Start: lsp.Position{Line: 0, Character: 6},
+			End:   lsp.Position{Line: 0, Character: 23},
+		},
+		SelectionRange: lsp.Range{
+			Start: lsp.Position{Line: 0, Character: 6},
+			End:   lsp.Position{Line: 0, Character: 9},
+		},
+	}
+
+	got := callHierarchyItem(item)
+	assert.Equal(t, expected, got)
+
+	roundTrip, err := fromCallHierarchyItem(got)
+	require.NoError(t, err)
+	assert.Equal(t, item, roundTrip)
+}
@@ -0,0 +1,88 @@
+package server
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/tminor/jsonnet-language-server/pkg/analysis/lexical"
+	"github.com/tminor/jsonnet-language-server/pkg/analysis/lexical/token"
+	"github.com/tminor/jsonnet-language-server/pkg/config"
+	"github.com/tminor/jsonnet-language-server/pkg/lsp"
+	"github.com/tminor/jsonnet-language-server/pkg/util/uri"
+)
+
+// textDocumentCodeAction offers quick fixes for the diagnostics in a range.
+// Indexes of unknown fields can be changed to the closest existing fields.
+func textDocumentCodeAction(ctx context.Context, r *request, c *config.Config) (interface{}, error) {
+	var params lsp.CodeActionParams
+	if err := r.Decode(&params); err != nil {
+		return nil, err
+	}
+
+	doc, err := c.Text(ctx, params.TextDocument.URI)
+	if err != nil {
+		return nil, err
+	}
+
+	path, err := uri.ToPath(params.TextDocument.URI)
+	if err != nil {
+		return nil, err
+	}
+
+	unknown, err := token.UnknownFields(path, doc.String(), c.NodeCache(), c.JsonnetLibPaths())
+	if err != nil {
+		return nil, err
+	}
+
+	return unknownFieldActions(params, unknown), nil
+}
+
+// unknownFieldActions creates an action for each field suggested for the
+// unknown fields in the requested range.
+func unknownFieldActions(params lsp.CodeActionParams, unknown []token.UnknownField) []lsp.CodeAction {
+	actions := []lsp.CodeAction{}
+
+	for i := range unknown {
+		r := unknown[i].Range.ToLSP()
+		if !rangesOverlap(r, params.Range) {
+			continue
+		}
+
+		var diagnostics []lsp.Diagnostic
+		for _, d := range params.Context.Diagnostics {
+			if d.Code == lexical.DiagnosticUnknownField && d.Range == r {
+				diagnostics = append(diagnostics, d)
+			}
+		}
+
+		for j, name := range unknown[i].Suggestions {
+			actions = append(actions, lsp.CodeAction{
+				Title:       fmt.Sprintf("Change to %q", name),
+				Kind:        lsp.CAKQuickFix,
+				Diagnostics: diagnostics,
+				IsPreferred: j == 0,
+				Edit: &lsp.WorkspaceEdit{
+					Changes: map[string][]lsp.TextEdit{
+						params.TextDocument.URI: {{Range: r, NewText: name}},
+					},
+				},
+			})
+		}
+	}
+
+	return actions
+}
+
+// rangesOverlap returns true if two ranges share a position. Ranges which
+// touch overlap, so a cursor at the end of a field name finds the field.
+func rangesOverlap(a, b lsp.Range) bool {
+	return !lspPositionBefore(a.End, b.Start) && !lspPositionBefore(b.End, a.Start)
+}
+
+func lspPositionBefore(a, b lsp.Position) bool {
+	if a.Line != b.Line {
+		return a.Line < b.Line
+	}
+
+	return a.Character < b.Character
+}
@@ -0,0 +1,97 @@
+package server
+
+import (
+	"testing"
+
+	"github.com/tminor/jsonnet-language-server/pkg/analysis/lexical"
+	"github.com/tminor/jsonnet-language-server/pkg/analysis/lexical/token"
+	"github.com/tminor/jsonnet-language-server/pkg/lsp"
+	jpos "github.com/tminor/jsonnet-language-server/pkg/util/position"
+	"github.com/stretchr/testify/assert"
+)
+
+func Test_unknownFieldActions(t *testing.T) {
+	unknown := []token.UnknownField{
+		{
+			Name:        "feild",
+			Range:       jpos.NewRangeFromCoords(2, 3, 2, 8),
+			Suggestions: []string{"field", "fields"},
+		},
+		{
+			Name:  "missing",
+			Range: jpos.NewRangeFromCoords(3, 3, 3, 10),
+		},
+	}
+
+	r := lsp.Range{
+		Start: lsp.Position{Line: 1, Character: 2},
+		End:   lsp.Position{Line: 1, Character: 7},
+	}
+
+	diagnostic := lsp.Diagnostic{
+		Range:    r,
+		Message:  `field "feild" does not exist; did you mean "field" or "fields"?`,
+		Severity: lsp.Warning,
+		Code:     lexical.DiagnosticUnknownField,
+	}
+
+	action := func(title, name string, preferred bool) lsp.CodeAction {
+		return lsp.CodeAction{
+			Title:       title,
+			Kind:        lsp.CAKQuickFix,
+			Diagnostics: []lsp.Diagnostic{diagnostic},
+			IsPreferred: preferred,
+			Edit: &lsp.WorkspaceEdit{
+				Changes: map[string][]lsp.TextEdit{
+					"file:///file.jsonnet": {{Range: r, NewText: name}},
+				},
+			},
+		}
+	}
+
+	cases := []struct {
+		name     string
+		r        lsp.Range
+		expected []lsp.CodeAction
+	}{
+		{
+			name: "cursor in field",
+			r: lsp.Range{
+				Start: lsp.Position{Line: 1, Character: 7},
+				End:   lsp.Position{Line: 1, Character: 7},
+			},
+			expected: []lsp.CodeAction{
+				action(`Change to "field"`, "field", true),
+				action(`Change to "fields"`, "fields", false),
+			},
+		},
+		{
+			name: "no suggestions",
+			r: lsp.Range{
+				Start: lsp.Position{Line: 2, Character: 3},
+				End:   lsp.Position{Line: 2, Character: 3},
+			},
+			expected: []lsp.CodeAction{},
+		},
+		{
+			name: "outside of fields",
+			r: lsp.Range{
+				Start: lsp.Position{Line: 0, Character: 0},
+				End:   lsp.Position{Line: 0, Character: 4},
+			},
+			expected: []lsp.CodeAction{},
+		},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			params := lsp.CodeActionParams{
+				TextDocument: lsp.TextDocumentIdentifier{URI: "file:///file.jsonnet"},
+				Range:        tc.r,
+				Context:      lsp.CodeActionContext{Diagnostics: []lsp.Diagnostic{diagnostic}},
+			}
+
+			assert.Equal(t, tc.expected, unknownFieldActions(params, unknown))
+		})
+	}
+}
@@ -0,0 +1,164 @@
+package server
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/tminor/jsonnet-language-server/pkg/analysis/lexical/token"
+	"github.com/tminor/jsonnet-language-server/pkg/config"
+	"github.com/tminor/jsonnet-language-server/pkg/lsp"
+	"github.com/tminor/jsonnet-language-server/pkg/util/uri"
+	opentracing "github.com/opentracing/opentracing-go"
+	"github.com/pkg/errors"
+)
+
+const (
+	// commandShowReferences is handled by the client. It shows the
+	// references to a declaration.
+	commandShowReferences = "jsonnet.showReferences"
+
+	// commandEvaluate evaluates a file or one of its fields and returns
+	// the JSON as a virtual document.
+	commandEvaluate = "jsonnet.evaluate"
+
+	// evaluatedScheme is the URI scheme of evaluated documents.
+	evaluatedScheme = "jsonnet-eval"
+)
+
+func textDocumentCodeLens(ctx context.Context, r *request, c *config.Config) (interface{}, error) {
+	span := opentracing.SpanFromContext(ctx)
+	ctx = opentracing.ContextWithSpan(ctx, span)
+
+	var params lsp.CodeLensParams
+	if err := r.Decode(&params); err != nil {
+		return nil, err
+	}
+
+	doc, err := c.Text(ctx, params.TextDocument.URI)
+	if err != nil {
+		return nil, err
+	}
+
+	path, err := uri.ToPath(params.TextDocument.URI)
+	if err != nil {
+		return nil, err
+	}
+
+	lenses, err := token.CodeLenses(path, doc.String(), c.NodeCache(), c.JsonnetLibPaths())
+	if err != nil {
+		return nil, err
+	}
+
+	return codeLenses(params.TextDocument.URI, lenses), nil
+}
+
+// codeLenses converts code lenses to LSP code lenses with the commands
+// they run.
+func codeLenses(docURI string, lenses []token.CodeLens) []lsp.CodeLens {
+	out := []lsp.CodeLens{}
+
+	for _, lens := range lenses {
+		cl := lsp.CodeLens{
+			Range: lens.Range.ToLSP(),
+		}
+
+		switch lens.Kind {
+		case token.CodeLensReferences:
+			locations := []lsp.Location{}
+			for _, l := range lens.References {
+				locations = append(locations, l.ToLSP())
+			}
+
+			title := fmt.Sprintf("%d references", len(locations))
+			if len(locations) == 1 {
+				title = "1 reference"
+			}
+
+			cl.Command = lsp.Command{
+				Title:     title,
+				Command:   commandShowReferences,
+				Arguments: []interface{}{docURI, cl.Range.Start, locations},
+			}
+		case token.CodeLensEvaluate:
+			path := append([]string{}, lens.Path...)
+
+			cl.Command = lsp.Command{
+				Title:     "Evaluate",
+				Command:   commandEvaluate,
+				Arguments: []interface{}{docURI, path},
+			}
+		}
+
+		out = append(out, cl)
+	}
+
+	return out
+}
+
+// evaluate evaluates a document, or the field at a path in the document if
+// a path is given. Its arguments are the document URI and the optional
+// path.
+func evaluate(ctx context.Context, args []interface{}, c *config.Config) (*lsp.VirtualDocument, error) {
+	if len(args) < 1 || len(args) > 2 {
+		return nil, errors.Errorf("%s expects 1 or 2 arguments", commandEvaluate)
+	}
+
+	docURI, ok := args[0].(string)
+	if !ok {
+		return nil, errors.Errorf("%s URI was not a string", commandEvaluate)
+	}
+
+	var fieldPath []string
+	if len(args) == 2 {
+		names, ok := args[1].([]interface{})
+		if !ok {
+			return nil, errors.Errorf("%s path was not an array", commandEvaluate)
+		}
+
+		for _, name := range names {
+			s, ok := name.(string)
+			if !ok {
+				return nil, errors.Errorf("%s path contains a non string", commandEvaluate)
+			}
+			fieldPath = append(fieldPath, s)
+		}
+	}
+
+	doc, err := c.Text(ctx, docURI)
+	if err != nil {
+		return nil, err
+	}
+
+	path, err := uri.ToPath(docURI)
+	if err != nil {
+		return nil, err
+	}
+
+	ic, err := token.NewIdentifyConfig(path, c.JsonnetLibPaths()...)
+	if err != nil {
+		return nil, err
+	}
+
+	out, err := token.Evaluate(path, doc.String(), fieldPath, ic)
+	if err != nil {
+		return nil, errors.Wrap(err, "evaluating")
+	}
+
+	return &lsp.VirtualDocument{
+		URI:        evaluatedURI(path, fieldPath),
+		LanguageID: "json",
+		Text:       out,
+	}, nil
+}
+
+// evaluatedURI is the URI of the virtual document for an evaluated file or
+// field.
+func evaluatedURI(path string, fieldPath []string) string {
+	u := fmt.Sprintf("%s://%s.json", evaluatedScheme, path)
+	if len(fieldPath) > 0 {
+		u += "#" + strings.Join(fieldPath, ".")
+	}
+
+	return u
+}
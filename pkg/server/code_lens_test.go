@@ -0,0 +1,78 @@
+package server
+
+import (
+	"testing"
+
+	"github.com/tminor/jsonnet-language-server/pkg/analysis/lexical/token"
+	"github.com/tminor/jsonnet-language-server/pkg/lsp"
+	jpos "github.com/tminor/jsonnet-language-server/pkg/util/position"
+	"github.com/stretchr/testify/assert"
+)
+
+func Test_codeLenses(t *testing.T) {
+	lenses := []token.CodeLens{
+		{
+			Kind:  token.CodeLensEvaluate,
+			Range: jpos.NewRangeFromCoords(1, 1, 1, 1),
+		},
+		{
+			Kind:  token.CodeLensReferences,
+			Range: jpos.NewRangeFromCoords(1, 7, 1, 8),
+			References: []jpos.Location{
+				jpos.NewLocation("/file.jsonnet", jpos.NewRangeFromCoords(2, 1, 2, 2)),
+			},
+		},
+		{
+			Kind:  token.CodeLensEvaluate,
+			Range: jpos.NewRangeFromCoords(3, 3, 3, 4),
+			Path:  []string{"f"},
+		},
+	}
+
+	start := lsp.Position{Line: 0, Character: 6}
+
+	expected := []lsp.CodeLens{
+		{
+			Range: lsp.Range{},
+			Command: lsp.Command{
+				Title:     "Evaluate",
+				Command:   commandEvaluate,
+				Arguments: []interface{}{"file:///file.jsonnet", []string{}},
+			},
+		},
+		{
+			Range: lsp.Range{Start: start, End: lsp.Position{Line: 0, Character: 7}},
+			Command: lsp.Command{
+				Title:   "1 reference",
+				Command: commandShowReferences,
+				Arguments: []interface{}{"file:///file.jsonnet", start, []lsp.Location{
+					{
+						URI: "file:///file.jsonnet",
+						Range: lsp.Range{
+							Start: lsp.Position{Line: 1, Character: 0},
+							End:   lsp.Position{Line: 1, Character: 1},
+						},
+					},
+				}},
+			},
+		},
+		{
+			Range: lsp.Range{
+				Start: lsp.Position{Line: 2, Character: 2},
+				End:   lsp.Position{Line: 2, Character: 3},
+			},
+			Command: lsp.Command{
+				Title:     "Evaluate",
+				Command:   commandEvaluate,
+				Arguments: []interface{}{"file:///file.jsonnet", []string{"f"}},
+			},
+		},
+	}
+
+	assert.Equal(t, expected, codeLenses("file:///file.jsonnet", lenses))
+}
+
+func Test_evaluatedURI(t *testing.T) {
+	assert.Equal(t, "jsonnet-eval:///app/main.jsonnet.json", evaluatedURI("/app/main.jsonnet", nil))
+	assert.Equal(t, "jsonnet-eval:///app/main.jsonnet.json#a.b", evaluatedURI("/app/main.jsonnet", []string{"a", "b"}))
+}
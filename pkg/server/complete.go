@@ -3,6 +3,8 @@ package server
 import (
 	"context"
 	"fmt"
+	"regexp"
+	"strconv"
 	"strings"
 
 	"github.com/tminor/jsonnet-language-server/pkg/tracing"
@@ -15,6 +17,7 @@ import (
 	"github.com/tminor/jsonnet-language-server/pkg/lsp"
 	"github.com/tminor/jsonnet-language-server/pkg/util/uri"
 	"github.com/davecgh/go-spew/spew"
+	"github.com/google/go-jsonnet/ast"
 	"github.com/pkg/errors"
 )
 
@@ -78,12 +81,7 @@ func (c *complete) handle(ctx context.Context) (interface{}, error) {
 		return nil, err
 	}
 
-	list := &lsp.CompletionList{
-		Items: []lsp.CompletionItem{},
-	}
-
 	pos := position.FromLSPPosition(c.referenceParams.Position)
-	editRange := position.NewRange(pos, pos)
 
 	span.LogFields(
 		log.String("truncate.to", pos.String()),
@@ -98,15 +96,39 @@ func (c *complete) handle(ctx context.Context) (interface{}, error) {
 		log.String("truncate.text", matchText),
 	)
 
+	query := wordBefore(text.String(), pos)
+	frecency := c.config.Frecency()
+	lspPos := c.referenceParams.Position
+
 	matchItems, err := c.completionMatcher.Match(ctx, pos, path, text.String())
 	if err != nil {
 		return nil, err
 	}
 
 	if len(matchItems) > 0 {
-		return matchItems, nil
+		// matchers complete after a trigger, so the client can filter the
+		// whole list as the user types.
+		candidates := toCandidates(matchItems)
+		setResolveContext(candidates, uriStr, lspPos)
+		return rankCompletionItems(candidates, query, frecency, 0), nil
 	}
 
+	fc, err := token.FieldCandidates(path, text.String(), pos, c.config.JsonnetLibPaths(), c.config.NodeCache())
+	if err != nil {
+		span.LogFields(
+			log.Error(err),
+		)
+	} else if fc != nil && len(fc.Fields) > 0 {
+		candidates := toCandidates(fieldCompletionItems(fc))
+		setResolveContext(candidates, uriStr, lspPos)
+		return rankCompletionItems(candidates, query, frecency, maxCompletionItems), nil
+	}
+
+	start := position.New(pos.Line(), pos.Column()-len(query))
+	editRange := position.NewRange(start, pos)
+
+	var candidates []completionCandidate
+
 	m, err := token.LocationScope(path, text.String(), pos, c.config.NodeCache())
 	if err != nil {
 		span.LogFields(
@@ -134,7 +156,12 @@ func (c *complete) handle(ctx context.Context) (interface{}, error) {
 				},
 			}
 
-			list.Items = append(list.Items, ci)
+			if k != "std" {
+				ci.Data = &completionResolve{Kind: resolveKindVariable, Name: k}
+			}
+
+			// variables in inner scopes are more likely to be used.
+			candidates = append(candidates, completionCandidate{item: ci, boost: e.Depth})
 		}
 	}
 
@@ -149,9 +176,120 @@ func (c *complete) handle(ctx context.Context) (interface{}, error) {
 			},
 		}
 
-		list.Items = append(list.Items, ci)
+		candidates = append(candidates, completionCandidate{item: ci})
+	}
+
+	snippetSupport := c.config.ClientCapabilities().TextDocument.Completion.CompletionItem.SnippetSupport
+	snippetItems := snippetCompletionItems(c.config.Snippets(), editRange, snippetSupport)
+	candidates = append(candidates, toCandidates(snippetItems)...)
+
+	setResolveContext(candidates, uriStr, lspPos)
+
+	return rankCompletionItems(candidates, query, frecency, maxCompletionItems), nil
+}
+
+func toCandidates(items []lsp.CompletionItem) []completionCandidate {
+	var candidates []completionCandidate
+	for _, item := range items {
+		candidates = append(candidates, completionCandidate{item: item})
+	}
+
+	return candidates
+}
+
+// snippetCompletionItems creates completion items for snippets. If the client
+// does not support snippets, the snippet is inserted as plain text.
+func snippetCompletionItems(snippets []langserver.Snippet, r position.Range, snippetSupport bool) []lsp.CompletionItem {
+	var items []lsp.CompletionItem
+
+	for _, snippet := range snippets {
+		ci := lsp.CompletionItem{
+			Label:            snippet.Label,
+			Kind:             lsp.CIKSnippet,
+			Detail:           snippet.Detail,
+			SortText:         fmt.Sprintf("2_%s", snippet.Label),
+			InsertTextFormat: lsp.ITFSnippet,
+			TextEdit: lsp.TextEdit{
+				Range:   r.ToLSP(),
+				NewText: snippet.Body,
+			},
+		}
+
+		if !snippetSupport {
+			ci.InsertTextFormat = lsp.ITFPlainText
+			ci.TextEdit.NewText = snippet.PlainText()
+		}
+
+		items = append(items, ci)
 	}
 
-	return list, nil
+	return items
+}
+
+var (
+	reIdentifier = regexp.MustCompile(`^[_a-zA-Z][_a-zA-Z0-9]*$`)
+)
+
+// fieldCompletionItems creates completion items for fields which can be set
+// in an object literal. Each field can be set with `:` or merged with `+:`.
+func fieldCompletionItems(fc *token.FieldCompletion) []lsp.CompletionItem {
+	var items []lsp.CompletionItem
+
+	keywords := (&token.Scope{}).Keywords()
+
+	for i := range fc.Fields {
+		field := fc.Fields[i]
+
+		key := field.Name
+		if !reIdentifier.MatchString(key) || stringInSlice(key, keywords) {
+			key = strconv.Quote(key)
+		}
+
+		// details are computed when the item is resolved.
+		var detail string
+		if field.IsHidden() {
+			detail = "(hidden)"
+		}
+		resolve := &completionResolve{Kind: resolveKindField, Name: field.Name}
+
+		visibility := field.Visibility()
+
+		if fn, ok := field.Node.(*ast.Function); ok {
+			var params []string
+			for _, param := range fn.Parameters.Required {
+				params = append(params, string(param))
+			}
+			for _, param := range fn.Parameters.Optional {
+				params = append(params, string(param.Name))
+			}
+
+			text := fmt.Sprintf("%s(%s)%s ", key, strings.Join(params, ", "), visibility)
+			ci := createCompletionItem(strings.TrimSpace(text), text, lsp.CIKMethod, fc.Range,
+				&token.ScopeEntry{Detail: detail})
+			ci.SortText = fmt.Sprintf("0_%s", field.Name)
+			ci.Data = resolve
+			items = append(items, ci)
+			continue
+		}
+
+		set := createCompletionItem(key+visibility, key+visibility+" ", lsp.CIKField, fc.Range,
+			&token.ScopeEntry{Detail: detail})
+		merge := createCompletionItem(key+"+"+visibility, key+"+"+visibility+" ", lsp.CIKField, fc.Range,
+			&token.ScopeEntry{Detail: detail})
+		set.Data = resolve
+		merge.Data = resolve
+
+		// objects are usually merged rather than replaced.
+		if _, ok := field.Node.(*ast.DesugaredObject); ok {
+			merge.SortText = fmt.Sprintf("0_%s_0", field.Name)
+			set.SortText = fmt.Sprintf("0_%s_1", field.Name)
+		} else {
+			set.SortText = fmt.Sprintf("0_%s_0", field.Name)
+			merge.SortText = fmt.Sprintf("0_%s_1", field.Name)
+		}
+
+		items = append(items, set, merge)
+	}
 
+	return items
 }
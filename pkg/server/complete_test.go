@@ -1,7 +1,142 @@
 package server
 
-import "testing"
+import (
+	"context"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/tminor/jsonnet-language-server/pkg/config"
+	"github.com/tminor/jsonnet-language-server/pkg/langserver"
+	"github.com/tminor/jsonnet-language-server/pkg/lsp"
+	"github.com/tminor/jsonnet-language-server/pkg/util/position"
+	opentracing "github.com/opentracing/opentracing-go"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
 
 func Test_complete(t *testing.T) {
+	dir, err := ioutil.TempDir("", "")
+	require.NoError(t, err)
+	defer os.RemoveAll(dir)
+
+	err = ioutil.WriteFile(filepath.Join(dir, "lib.libsonnet"), []byte("{a: 1, b: 2}"), 0600)
+	require.NoError(t, err)
+
+	cases := []struct {
+		name     string
+		source   string
+		pos      position.Position
+		expected func(items []lsp.CompletionItem)
+	}{
+		{
+			name:   "below an import",
+			source: "local lib = import 'lib.libsonnet';\n\nlib",
+			pos:    position.New(2, 1),
+			expected: func(items []lsp.CompletionItem) {
+				require.NotEmpty(t, items)
+				for _, item := range items {
+					assert.NotEqual(t, lsp.CIKFile, item.Kind, item.Label)
+					assert.NotEqual(t, lsp.CIKFolder, item.Kind, item.Label)
+				}
+			},
+		},
+		{
+			name:   "index of an import",
+			source: "(import 'lib.libsonnet').",
+			pos:    position.New(1, 26),
+			expected: func(items []lsp.CompletionItem) {
+				var labels []string
+				for _, item := range items {
+					labels = append(labels, item.Label)
+				}
+
+				assert.ElementsMatch(t, []string{"a", "b"}, labels)
+			},
+		},
+		{
+			name:   "not an index",
+			source: "local x = 1.",
+			pos:    position.New(1, 13),
+			expected: func(items []lsp.CompletionItem) {
+				for _, item := range items {
+					assert.NotEqual(t, lsp.CIKField, item.Kind, item.Label)
+				}
+			},
+		},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			span := opentracing.StartSpan("complete")
+			defer span.Finish()
+			ctx := opentracing.ContextWithSpan(context.Background(), span)
+
+			uriStr := "file://" + filepath.Join(dir, "file.jsonnet")
+
+			c := config.New()
+			err := c.StoreTextDocumentItem(ctx, config.NewTextDocument(uriStr, tc.source))
+			require.NoError(t, err)
+
+			rp := lsp.ReferenceParams{
+				TextDocumentPositionParams: lsp.TextDocumentPositionParams{
+					TextDocument: lsp.TextDocumentIdentifier{URI: uriStr},
+					Position:     tc.pos.ToLSP(),
+				},
+			}
+
+			cmpl, err := newComplete(rp, c)
+			require.NoError(t, err)
+
+			got, err := cmpl.handle(ctx)
+			require.NoError(t, err)
+
+			list, ok := got.(*lsp.CompletionList)
+			require.True(t, ok)
+
+			tc.expected(list.Items)
+		})
+	}
+}
+
+func Test_snippetCompletionItems(t *testing.T) {
+	snippets := []langserver.Snippet{
+		{Label: "local", Detail: "local variable", Body: "local ${1:name} = ${0};"},
+	}
+
+	r := position.NewRangeFromCoords(1, 1, 1, 1)
+
+	cases := []struct {
+		name           string
+		snippetSupport bool
+		format         lsp.InsertTextFormat
+		text           string
+	}{
+		{
+			name:           "client supports snippets",
+			snippetSupport: true,
+			format:         lsp.ITFSnippet,
+			text:           "local ${1:name} = ${0};",
+		},
+		{
+			name:   "client does not support snippets",
+			format: lsp.ITFPlainText,
+			text:   "local name = ;",
+		},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			items := snippetCompletionItems(snippets, r, tc.snippetSupport)
+			require.Len(t, items, 1)
 
+			item := items[0]
+			assert.Equal(t, "local", item.Label)
+			assert.Equal(t, lsp.CIKSnippet, item.Kind)
+			assert.Equal(t, "local variable", item.Detail)
+			assert.Equal(t, tc.format, item.InsertTextFormat)
+			assert.Equal(t, tc.text, item.TextEdit.NewText)
+		})
+	}
 }
@@ -0,0 +1,174 @@
+package server
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/tminor/jsonnet-language-server/pkg/langserver"
+	"github.com/tminor/jsonnet-language-server/pkg/lsp"
+	"github.com/tminor/jsonnet-language-server/pkg/util/position"
+)
+
+const (
+	// commandCompletionAccepted is sent by the client when a completion
+	// item is accepted.
+	commandCompletionAccepted = "jsonnet.completion.accepted"
+
+	// maxCompletionItems is the number of items returned before the list
+	// is marked incomplete.
+	maxCompletionItems = 200
+
+	boostWeight    = 5
+	frecencyWeight = 20
+
+	// acceptedWeight is the frecency weight of an accepted completion.
+	acceptedWeight = 1.0
+	// resolvedWeight is the frecency weight of a completion resolved by the
+	// client. Clients resolve the selected item, so it counts for less than
+	// an accepted item.
+	resolvedWeight = 0.25
+)
+
+var (
+	kindWeights = map[int]int{
+		lsp.CIKVariable: 20,
+		lsp.CIKField:    20,
+		lsp.CIKMethod:   20,
+		lsp.CIKFile:     20,
+		lsp.CIKFolder:   20,
+		lsp.CIKKeyword:  10,
+		lsp.CIKSnippet:  0,
+	}
+)
+
+// completionCandidate is a completion item being ranked.
+type completionCandidate struct {
+	item lsp.CompletionItem
+	// boost raises the rank of the item, e.g. for variables declared in
+	// inner scopes.
+	boost int
+}
+
+// completionData is sent with completion items so usage can be recorded and
+// details can be computed when the item is resolved.
+type completionData struct {
+	FrecencyKey string             `json:"frecencyKey"`
+	Resolve     *completionResolve `json:"resolve,omitempty"`
+}
+
+type rankedCandidate struct {
+	completionCandidate
+	score int
+}
+
+// rankCompletionItems filters candidates which fuzzy match the query and
+// sorts them by match score, boost, kind and frecency. If limit is greater
+// than 0 and more than limit items match, the list is truncated and marked
+// incomplete so the client asks again as the user types.
+func rankCompletionItems(candidates []completionCandidate, query string, frecency *langserver.Frecency, limit int) *lsp.CompletionList {
+	var ranked []rankedCandidate
+
+	for _, candidate := range candidates {
+		text := candidate.item.FilterText
+		if text == "" {
+			text = candidate.item.Label
+		}
+
+		score, ok := langserver.FuzzyMatch(query, text)
+		if !ok {
+			continue
+		}
+
+		key := frecencyKey(candidate.item)
+
+		score += candidate.boost * boostWeight
+		score += kindWeights[candidate.item.Kind]
+		score += int(frecency.Score(key) * frecencyWeight)
+
+		rc := rankedCandidate{
+			completionCandidate: candidate,
+			score:               score,
+		}
+
+		data := completionData{FrecencyKey: key}
+		if r, ok := candidate.item.Data.(*completionResolve); ok {
+			data.Resolve = r
+		}
+		rc.item.Data = data
+		rc.item.Command = &lsp.Command{
+			Title:     "completion accepted",
+			Command:   commandCompletionAccepted,
+			Arguments: []interface{}{key},
+		}
+
+		ranked = append(ranked, rc)
+	}
+
+	// the existing sort text breaks ties.
+	sort.SliceStable(ranked, func(i, j int) bool {
+		if ranked[i].score != ranked[j].score {
+			return ranked[i].score > ranked[j].score
+		}
+
+		if ranked[i].item.SortText != ranked[j].item.SortText {
+			return ranked[i].item.SortText < ranked[j].item.SortText
+		}
+
+		return ranked[i].item.Label < ranked[j].item.Label
+	})
+
+	list := &lsp.CompletionList{
+		Items: []lsp.CompletionItem{},
+	}
+
+	if limit > 0 && len(ranked) > limit {
+		ranked = ranked[:limit]
+		list.IsIncomplete = true
+	}
+
+	for i := range ranked {
+		item := ranked[i].item
+		item.SortText = fmt.Sprintf("%04d", i)
+		list.Items = append(list.Items, item)
+	}
+
+	return list
+}
+
+// frecencyKey is the key used to record usage of a completion item.
+func frecencyKey(item lsp.CompletionItem) string {
+	return fmt.Sprintf("%d:%s", item.Kind, item.Label)
+}
+
+// wordBefore returns the identifier characters before a position.
+func wordBefore(source string, pos position.Position) string {
+	lines := strings.Split(source, "\n")
+	if pos.Line() < 1 || pos.Line() > len(lines) {
+		return ""
+	}
+
+	line := lines[pos.Line()-1]
+
+	end := pos.Column() - 1
+	if end > len(line) {
+		end = len(line)
+	}
+	if end < 0 {
+		return ""
+	}
+
+	start := end
+	for start > 0 && isIdentifierChar(line[start-1]) {
+		start--
+	}
+
+	return line[start:end]
+}
+
+func isIdentifierChar(c byte) bool {
+	return c == '_' ||
+		(c >= 'a' && c <= 'z') ||
+		(c >= 'A' && c <= 'Z') ||
+		(c >= '0' && c <= '9')
+}
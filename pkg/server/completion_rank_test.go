@@ -0,0 +1,117 @@
+package server
+
+import (
+	"testing"
+
+	"github.com/tminor/jsonnet-language-server/pkg/langserver"
+	"github.com/tminor/jsonnet-language-server/pkg/lsp"
+	"github.com/tminor/jsonnet-language-server/pkg/util/position"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func Test_rankCompletionItems(t *testing.T) {
+	variable := func(label string, boost int) completionCandidate {
+		return completionCandidate{
+			item:  lsp.CompletionItem{Label: label, Kind: lsp.CIKVariable},
+			boost: boost,
+		}
+	}
+
+	keyword := func(label string) completionCandidate {
+		return completionCandidate{
+			item: lsp.CompletionItem{Label: label, Kind: lsp.CIKKeyword},
+		}
+	}
+
+	cases := []struct {
+		name         string
+		candidates   []completionCandidate
+		query        string
+		used         []string
+		limit        int
+		expected     []string
+		isIncomplete bool
+	}{
+		{
+			name:       "filters by query",
+			candidates: []completionCandidate{variable("container", 0), variable("deployment", 0), variable("cat", 0)},
+			query:      "con",
+			expected:   []string{"container"},
+		},
+		{
+			name:       "inner scope first",
+			candidates: []completionCandidate{variable("outer", 1), variable("inner", 3)},
+			expected:   []string{"inner", "outer"},
+		},
+		{
+			name:       "variables before keywords",
+			candidates: []completionCandidate{keyword("local"), variable("loc", 0)},
+			query:      "lo",
+			expected:   []string{"loc", "local"},
+		},
+		{
+			name:       "frecently used first",
+			candidates: []completionCandidate{variable("a", 0), variable("b", 0)},
+			used:       []string{"6:b"},
+			expected:   []string{"b", "a"},
+		},
+		{
+			name:         "limit marks list incomplete",
+			candidates:   []completionCandidate{variable("a", 0), variable("b", 0), variable("c", 0)},
+			limit:        2,
+			expected:     []string{"a", "b"},
+			isIncomplete: true,
+		},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			frecency := langserver.NewFrecency()
+			for _, key := range tc.used {
+				frecency.Record(key, acceptedWeight)
+			}
+
+			list := rankCompletionItems(tc.candidates, tc.query, frecency, tc.limit)
+
+			var got []string
+			for i, item := range list.Items {
+				got = append(got, item.Label)
+
+				key := frecencyKey(item)
+				assert.Equal(t, completionData{FrecencyKey: key}, item.Data)
+				require.NotNil(t, item.Command)
+				assert.Equal(t, commandCompletionAccepted, item.Command.Command)
+				assert.Equal(t, []interface{}{key}, item.Command.Arguments)
+
+				if i > 0 {
+					assert.True(t, list.Items[i-1].SortText < item.SortText)
+				}
+			}
+
+			assert.Equal(t, tc.expected, got)
+			assert.Equal(t, tc.isIncomplete, list.IsIncomplete)
+		})
+	}
+}
+
+func Test_wordBefore(t *testing.T) {
+	cases := []struct {
+		name     string
+		source   string
+		pos      position.Position
+		expected string
+	}{
+		{name: "end of line", source: "local foo = ba", pos: position.New(1, 15), expected: "ba"},
+		{name: "middle of word", source: "local foo = bar", pos: position.New(1, 15), expected: "ba"},
+		{name: "after operator", source: "a + ", pos: position.New(1, 5), expected: ""},
+		{name: "second line", source: "local a = 1;\nstd.le", pos: position.New(2, 7), expected: "le"},
+		{name: "invalid line", source: "a", pos: position.New(3, 1), expected: ""},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			assert.Equal(t, tc.expected, wordBefore(tc.source, tc.pos))
+		})
+	}
+}
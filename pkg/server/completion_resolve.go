@@ -0,0 +1,163 @@
+package server
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"strings"
+
+	"github.com/tminor/jsonnet-language-server/pkg/analysis/lexical/token"
+	"github.com/tminor/jsonnet-language-server/pkg/config"
+	"github.com/tminor/jsonnet-language-server/pkg/lsp"
+	"github.com/tminor/jsonnet-language-server/pkg/tracing"
+	"github.com/tminor/jsonnet-language-server/pkg/util/position"
+	"github.com/tminor/jsonnet-language-server/pkg/util/text"
+	"github.com/tminor/jsonnet-language-server/pkg/util/uri"
+	"github.com/opentracing/opentracing-go/log"
+	"github.com/pkg/errors"
+)
+
+const (
+	// resolveKindIndex is a field of an indexed expression, e.g. `o.`.
+	resolveKindIndex = "index"
+	// resolveKindField is a field which can be set in an object literal.
+	resolveKindField = "field"
+	// resolveKindVariable is a variable in scope.
+	resolveKindVariable = "variable"
+)
+
+// completionResolve is a handle used to compute the details of a completion
+// item when it is resolved. Computing details for every item in a large
+// object is slow, so the completion response only includes the handle.
+type completionResolve struct {
+	Kind     string       `json:"kind"`
+	Name     string       `json:"name"`
+	URI      string       `json:"uri,omitempty"`
+	Position lsp.Position `json:"position"`
+}
+
+// setResolveContext sets the document and position on completion candidates
+// which can be resolved.
+func setResolveContext(candidates []completionCandidate, uriStr string, pos lsp.Position) {
+	for i := range candidates {
+		if r, ok := candidates[i].item.Data.(*completionResolve); ok {
+			r.URI = uriStr
+			r.Position = pos
+		}
+	}
+}
+
+func completionItemResolve(ctx context.Context, r *request, c *config.Config) (interface{}, error) {
+	span, ctx := tracing.ChildSpan(ctx, "completionItemResolve")
+	defer span.Finish()
+
+	var ci lsp.CompletionItem
+	if err := r.Decode(&ci); err != nil {
+		return nil, err
+	}
+
+	data, err := decodeCompletionData(ci.Data)
+	if err != nil {
+		span.LogFields(log.Error(err))
+		return ci, nil
+	}
+
+	// clients resolve the selected item, so record it as a weak use.
+	if data.FrecencyKey != "" {
+		c.Frecency().Record(data.FrecencyKey, resolvedWeight)
+	}
+
+	if data.Resolve == nil {
+		return ci, nil
+	}
+
+	cd, err := resolveCompletionDetail(ctx, c, data.Resolve)
+	if err != nil {
+		// the item is still usable without details.
+		span.LogFields(log.Error(err))
+		return ci, nil
+	}
+
+	if cd.Detail != "" {
+		ci.Detail = cd.Detail
+	}
+	ci.Documentation = completionDocumentation(cd)
+
+	return ci, nil
+}
+
+// decodeCompletionData decodes the data sent back by the client.
+func decodeCompletionData(v interface{}) (*completionData, error) {
+	var data completionData
+	if v == nil {
+		return &data, nil
+	}
+
+	b, err := json.Marshal(v)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := json.Unmarshal(b, &data); err != nil {
+		return nil, errors.Wrap(err, "decoding completion item data")
+	}
+
+	return &data, nil
+}
+
+func resolveCompletionDetail(ctx context.Context, c *config.Config, cr *completionResolve) (*token.CompletionDetail, error) {
+	doc, err := c.Text(ctx, cr.URI)
+	if err != nil {
+		return nil, err
+	}
+
+	path, err := uri.ToPath(cr.URI)
+	if err != nil {
+		return nil, err
+	}
+
+	pos := position.FromLSPPosition(cr.Position)
+
+	identifyConfig, err := token.NewIdentifyConfig(path, c.JsonnetLibPaths()...)
+	if err != nil {
+		return nil, err
+	}
+
+	switch cr.Kind {
+	case resolveKindIndex:
+		// index completion works on the source before the position.
+		source, err := text.Truncate(doc.String(), pos)
+		if err != nil {
+			return nil, err
+		}
+
+		return token.IndexFieldDetail(source, pos, cr.Name, c.NodeCache(), identifyConfig)
+	case resolveKindField:
+		return token.FieldCandidateDetail(doc.String(), pos, cr.Name, c.NodeCache(), identifyConfig)
+	case resolveKindVariable:
+		return token.VariableDetail(doc.String(), pos, cr.Name, c.NodeCache(), identifyConfig)
+	default:
+		return nil, errors.Errorf("unknown completion kind %q", cr.Kind)
+	}
+}
+
+// completionDocumentation combines the doc comment, value preview and
+// location of a completion item.
+func completionDocumentation(cd *token.CompletionDetail) string {
+	var parts []string
+
+	if cd.Documentation != "" {
+		parts = append(parts, cd.Documentation)
+	}
+
+	if cd.Value != "" {
+		parts = append(parts, cd.Value)
+	}
+
+	if cd.Location != nil {
+		r := cd.Location.Range()
+		parts = append(parts, fmt.Sprintf("Defined in %s:%d", cd.Location.URI(), r.Start.Line()))
+	}
+
+	return strings.Join(parts, "\n\n")
+}
@@ -0,0 +1,78 @@
+package server
+
+import (
+	"testing"
+
+	"github.com/tminor/jsonnet-language-server/pkg/analysis/lexical/token"
+	"github.com/tminor/jsonnet-language-server/pkg/lsp"
+	"github.com/tminor/jsonnet-language-server/pkg/util/position"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func Test_decodeCompletionData(t *testing.T) {
+	// data sent back by the client is decoded as a map.
+	v := map[string]interface{}{
+		"frecencyKey": "5:a",
+		"resolve": map[string]interface{}{
+			"kind":     "index",
+			"name":     "a",
+			"uri":      "file:///file.jsonnet",
+			"position": map[string]interface{}{"line": 1, "character": 2},
+		},
+	}
+
+	data, err := decodeCompletionData(v)
+	require.NoError(t, err)
+
+	expected := &completionData{
+		FrecencyKey: "5:a",
+		Resolve: &completionResolve{
+			Kind:     resolveKindIndex,
+			Name:     "a",
+			URI:      "file:///file.jsonnet",
+			Position: lsp.Position{Line: 1, Character: 2},
+		},
+	}
+
+	assert.Equal(t, expected, data)
+
+	data, err = decodeCompletionData(nil)
+	require.NoError(t, err)
+	assert.Equal(t, &completionData{}, data)
+}
+
+func Test_completionDocumentation(t *testing.T) {
+	loc := position.NewLocation("/file.jsonnet", position.NewRangeFromCoords(3, 1, 3, 2))
+
+	cases := []struct {
+		name     string
+		cd       *token.CompletionDetail
+		expected string
+	}{
+		{
+			name: "all parts",
+			cd: &token.CompletionDetail{
+				Documentation: "doc",
+				Value:         "42",
+				Location:      &loc,
+			},
+			expected: "doc\n\n42\n\nDefined in /file.jsonnet:3",
+		},
+		{
+			name:     "value only",
+			cd:       &token.CompletionDetail{Value: "42"},
+			expected: "42",
+		},
+		{
+			name: "empty",
+			cd:   &token.CompletionDetail{},
+		},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			assert.Equal(t, tc.expected, completionDocumentation(tc.cd))
+		})
+	}
+}
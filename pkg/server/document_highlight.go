@@ -32,20 +32,32 @@ func textDocumentHighlight(ctx context.Context, r *request, c *config.Config) (i
 
 	pos := jpos.FromLSPPosition(params.Position)
 
-	locations, err := token.Highlight(ctx, path, doc.String(), pos, c.NodeCache())
+	highlights, err := token.Highlight(ctx, path, doc.String(), pos, c.NodeCache())
 	if err != nil {
 		return nil, err
 	}
 
-	var highlights []lsp.DocumentHighlight
+	return documentHighlights(highlights), nil
+}
+
+// documentHighlights converts highlights to LSP document highlights.
+// Declarations are writes and references are reads.
+func documentHighlights(highlights []token.DocumentHighlight) []lsp.DocumentHighlight {
+	out := []lsp.DocumentHighlight{}
 
-	for _, location := range locations.Slice() {
-		r := location.Range()
+	for _, highlight := range highlights {
+		r := highlight.Location.Range()
 		dh := lsp.DocumentHighlight{
 			Range: r.ToLSP(),
+			Kind:  lsp.Read,
 		}
-		highlights = append(highlights, dh)
+
+		if highlight.Kind == token.HighlightWrite {
+			dh.Kind = lsp.Write
+		}
+
+		out = append(out, dh)
 	}
 
-	return highlights, nil
+	return out
 }
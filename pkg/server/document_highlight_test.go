@@ -0,0 +1,42 @@
+package server
+
+import (
+	"testing"
+
+	"github.com/tminor/jsonnet-language-server/pkg/analysis/lexical/token"
+	"github.com/tminor/jsonnet-language-server/pkg/lsp"
+	jpos "github.com/tminor/jsonnet-language-server/pkg/util/position"
+	"github.com/stretchr/testify/assert"
+)
+
+func Test_documentHighlights(t *testing.T) {
+	highlights := []token.DocumentHighlight{
+		{
+			Location: jpos.NewLocation("file.jsonnet", jpos.NewRangeFromCoords(1, 7, 1, 8)),
+			Kind:     token.HighlightWrite,
+		},
+		{
+			Location: jpos.NewLocation("file.jsonnet", jpos.NewRangeFromCoords(2, 1, 2, 2)),
+			Kind:     token.HighlightRead,
+		},
+	}
+
+	expected := []lsp.DocumentHighlight{
+		{
+			Range: lsp.Range{
+				Start: lsp.Position{Line: 0, Character: 6},
+				End:   lsp.Position{Line: 0, Character: 7},
+			},
+			Kind: lsp.Write,
+		},
+		{
+			Range: lsp.Range{
+				Start: lsp.Position{Line: 1, Character: 0},
+				End:   lsp.Position{Line: 1, Character: 1},
+			},
+			Kind: lsp.Read,
+		},
+	}
+
+	assert.Equal(t, expected, documentHighlights(highlights))
+}
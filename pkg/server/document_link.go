@@ -0,0 +1,65 @@
+package server
+
+import (
+	"context"
+	"fmt"
+	"path/filepath"
+
+	"github.com/tminor/jsonnet-language-server/pkg/analysis/lexical/token"
+	"github.com/tminor/jsonnet-language-server/pkg/config"
+	"github.com/tminor/jsonnet-language-server/pkg/lsp"
+	"github.com/tminor/jsonnet-language-server/pkg/util/uri"
+	opentracing "github.com/opentracing/opentracing-go"
+)
+
+func textDocumentLink(ctx context.Context, r *request, c *config.Config) (interface{}, error) {
+	span := opentracing.SpanFromContext(ctx)
+	ctx = opentracing.ContextWithSpan(ctx, span)
+
+	var params lsp.DocumentLinkParams
+	if err := r.Decode(&params); err != nil {
+		return nil, err
+	}
+
+	doc, err := c.Text(ctx, params.TextDocument.URI)
+	if err != nil {
+		return nil, err
+	}
+
+	path, err := uri.ToPath(params.TextDocument.URI)
+	if err != nil {
+		return nil, err
+	}
+
+	links, err := token.ImportLinks(path, doc.String(), c.JsonnetLibPaths())
+	if err != nil {
+		return nil, err
+	}
+
+	return documentLinks(path, links), nil
+}
+
+// documentLinks converts resolved imports to LSP document links.
+// Unresolved imports are reported as diagnostics instead.
+func documentLinks(importer string, links []token.ImportLink) []lsp.DocumentLink {
+	out := []lsp.DocumentLink{}
+
+	for _, link := range links {
+		if !link.IsResolved() {
+			continue
+		}
+
+		tooltip := fmt.Sprintf("Found in lib path %s", link.LibPath)
+		if link.LibPath == filepath.Dir(importer) {
+			tooltip = fmt.Sprintf("Found relative to %s", filepath.Base(importer))
+		}
+
+		out = append(out, lsp.DocumentLink{
+			Range:   link.Range.ToLSP(),
+			Target:  uri.FromPath(link.Path),
+			Tooltip: tooltip,
+		})
+	}
+
+	return out
+}
@@ -0,0 +1,66 @@
+package server
+
+import (
+	"testing"
+
+	"github.com/tminor/jsonnet-language-server/pkg/analysis/lexical/token"
+	"github.com/tminor/jsonnet-language-server/pkg/lsp"
+	jpos "github.com/tminor/jsonnet-language-server/pkg/util/position"
+	"github.com/stretchr/testify/assert"
+)
+
+func Test_documentLinks(t *testing.T) {
+	links := []token.ImportLink{
+		{
+			Name:    "local.libsonnet",
+			Range:   jpos.NewRangeFromCoords(1, 19, 1, 34),
+			Path:    "/app/local.libsonnet",
+			LibPath: "/app",
+		},
+		{
+			Name:    "lib.libsonnet",
+			Range:   jpos.NewRangeFromCoords(2, 19, 2, 32),
+			Path:    "/lib/lib.libsonnet",
+			LibPath: "/lib",
+		},
+		{
+			Name:    "my lib#1.libsonnet",
+			Range:   jpos.NewRangeFromCoords(3, 19, 3, 37),
+			Path:    "/lib/my lib#1.libsonnet",
+			LibPath: "/lib",
+		},
+		{
+			Name:  "missing.libsonnet",
+			Range: jpos.NewRangeFromCoords(4, 19, 4, 36),
+		},
+	}
+
+	expected := []lsp.DocumentLink{
+		{
+			Range: lsp.Range{
+				Start: lsp.Position{Line: 0, Character: 18},
+				End:   lsp.Position{Line: 0, Character: 33},
+			},
+			Target:  "file:///app/local.libsonnet",
+			Tooltip: "Found relative to main.jsonnet",
+		},
+		{
+			Range: lsp.Range{
+				Start: lsp.Position{Line: 1, Character: 18},
+				End:   lsp.Position{Line: 1, Character: 31},
+			},
+			Target:  "file:///lib/lib.libsonnet",
+			Tooltip: "Found in lib path /lib",
+		},
+		{
+			Range: lsp.Range{
+				Start: lsp.Position{Line: 2, Character: 18},
+				End:   lsp.Position{Line: 2, Character: 36},
+			},
+			Target:  "file:///lib/my%20lib%231.libsonnet",
+			Tooltip: "Found in lib path /lib",
+		},
+	}
+
+	assert.Equal(t, expected, documentLinks("/app/main.jsonnet", links))
+}
@@ -0,0 +1,36 @@
+package server
+
+import (
+	"context"
+
+	"github.com/tminor/jsonnet-language-server/pkg/config"
+	"github.com/tminor/jsonnet-language-server/pkg/lsp"
+	"github.com/pkg/errors"
+)
+
+func workspaceExecuteCommand(ctx context.Context, r *request, c *config.Config) (interface{}, error) {
+	var params lsp.ExecuteCommandParams
+	if err := r.Decode(&params); err != nil {
+		return nil, err
+	}
+
+	switch params.Command {
+	case commandCompletionAccepted:
+		if len(params.Arguments) != 1 {
+			return nil, errors.Errorf("%s expects 1 argument", params.Command)
+		}
+
+		key, ok := params.Arguments[0].(string)
+		if !ok {
+			return nil, errors.Errorf("%s argument was not a string", params.Command)
+		}
+
+		c.Frecency().Record(key, acceptedWeight)
+	case commandEvaluate:
+		return evaluate(ctx, params.Arguments, c)
+	default:
+		return nil, errors.Errorf("unknown command %q", params.Command)
+	}
+
+	return nil, nil
+}
@@ -0,0 +1,62 @@
+package server
+
+import (
+	"context"
+
+	"github.com/tminor/jsonnet-language-server/pkg/analysis/lexical/token"
+	"github.com/tminor/jsonnet-language-server/pkg/config"
+	"github.com/tminor/jsonnet-language-server/pkg/lsp"
+	"github.com/tminor/jsonnet-language-server/pkg/util/uri"
+	opentracing "github.com/opentracing/opentracing-go"
+)
+
+func textDocumentFoldingRange(ctx context.Context, r *request, c *config.Config) (interface{}, error) {
+	span := opentracing.SpanFromContext(ctx)
+	ctx = opentracing.ContextWithSpan(ctx, span)
+
+	var params lsp.FoldingRangeParams
+	if err := r.Decode(&params); err != nil {
+		return nil, err
+	}
+
+	doc, err := c.Text(ctx, params.TextDocument.URI)
+	if err != nil {
+		return nil, err
+	}
+
+	path, err := uri.ToPath(params.TextDocument.URI)
+	if err != nil {
+		return nil, err
+	}
+
+	ranges, err := token.FoldingRanges(path, doc.String())
+	if err != nil {
+		return nil, err
+	}
+
+	return foldingRanges(ranges), nil
+}
+
+// foldingRanges converts folding ranges to LSP folding ranges. Code is
+// folded without a kind.
+func foldingRanges(ranges []token.FoldingRange) []lsp.FoldingRange {
+	out := []lsp.FoldingRange{}
+
+	for _, fr := range ranges {
+		lfr := lsp.FoldingRange{
+			StartLine: fr.StartLine - 1,
+			EndLine:   fr.EndLine - 1,
+		}
+
+		switch fr.Kind {
+		case token.FoldingComment:
+			lfr.Kind = lsp.FRKComment
+		case token.FoldingImports:
+			lfr.Kind = lsp.FRKImports
+		}
+
+		out = append(out, lfr)
+	}
+
+	return out
+}
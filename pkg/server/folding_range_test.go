@@ -0,0 +1,25 @@
+package server
+
+import (
+	"testing"
+
+	"github.com/tminor/jsonnet-language-server/pkg/analysis/lexical/token"
+	"github.com/tminor/jsonnet-language-server/pkg/lsp"
+	"github.com/stretchr/testify/assert"
+)
+
+func Test_foldingRanges(t *testing.T) {
+	ranges := []token.FoldingRange{
+		{StartLine: 1, EndLine: 3, Kind: token.FoldingImports},
+		{StartLine: 5, EndLine: 7, Kind: token.FoldingComment},
+		{StartLine: 8, EndLine: 12, Kind: token.FoldingCode},
+	}
+
+	expected := []lsp.FoldingRange{
+		{StartLine: 0, EndLine: 2, Kind: lsp.FRKImports},
+		{StartLine: 4, EndLine: 6, Kind: lsp.FRKComment},
+		{StartLine: 7, EndLine: 11},
+	}
+
+	assert.Equal(t, expected, foldingRanges(ranges))
+}
@@ -27,19 +27,38 @@ import (
 type operation func(context.Context, *request, *config.Config) (interface{}, error)
 
 var operations = map[string]operation{
-	"completionItem/resolve":         completionItemResolve,
-	"initialize":                     initialize,
-	"textDocument/completion":        textDocumentCompletion,
-	"textDocument/didChange":         textDocumentDidChange,
-	"textDocument/didClose":          textDocumentDidClose,
-	"textDocument/didOpen":           textDocumentDidOpen,
-	"textDocument/didSave":           textDocumentDidSave,
-	"textDocument/documentHighlight": textDocumentHighlight,
-	"textDocument/documentSymbol":    textDocumentSymbol,
-	"textDocument/hover":             textDocumentHover,
-	"textDocument/references":        textDocumentReferences,
-	"textDocument/signatureHelp":     textDocumentSignatureHelper,
-	"updateClientConfiguration":      updateClientConfiguration,
+	"callHierarchy/incomingCalls":            callHierarchyIncomingCalls,
+	"callHierarchy/outgoingCalls":            callHierarchyOutgoingCalls,
+	"completionItem/resolve":                 completionItemResolve,
+	"initialize":                             initialize,
+	"textDocument/codeAction":                textDocumentCodeAction,
+	"textDocument/codeLens":                  textDocumentCodeLens,
+	"textDocument/completion":                textDocumentCompletion,
+	"textDocument/didChange":                 textDocumentDidChange,
+	"textDocument/didClose":                  textDocumentDidClose,
+	"textDocument/didOpen":                   textDocumentDidOpen,
+	"textDocument/didSave":                   textDocumentDidSave,
+	"textDocument/documentHighlight":         textDocumentHighlight,
+	"textDocument/documentLink":              textDocumentLink,
+	"textDocument/documentSymbol":            textDocumentSymbol,
+	"textDocument/foldingRange":              textDocumentFoldingRange,
+	"textDocument/hover":                     textDocumentHover,
+	"textDocument/implementation":            textDocumentImplementation,
+	"textDocument/inlayHint":                 textDocumentInlayHint,
+	"textDocument/prepareCallHierarchy":      textDocumentPrepareCallHierarchy,
+	"textDocument/prepareTypeHierarchy":      textDocumentPrepareTypeHierarchy,
+	"textDocument/references":                textDocumentReferences,
+	"textDocument/selectionRange":            textDocumentSelectionRange,
+	"textDocument/semanticTokens/full":       textDocumentSemanticTokensFull,
+	"textDocument/semanticTokens/full/delta": textDocumentSemanticTokensFullDelta,
+	"textDocument/semanticTokens/range":      textDocumentSemanticTokensRange,
+	"textDocument/signatureHelp":             textDocumentSignatureHelper,
+	"typeHierarchy/subtypes":                 typeHierarchySubtypes,
+	"typeHierarchy/supertypes":               typeHierarchySupertypes,
+	"updateClientConfiguration":              updateClientConfiguration,
+	"workspace/didChangeWatchedFiles":        workspaceDidChangeWatchedFiles,
+	"workspace/executeCommand":               workspaceExecuteCommand,
+	"workspace/symbol":                       workspaceSymbol,
 }
 
 // Handler is a JSON RPC Handler
@@ -63,7 +82,7 @@ func NewHandler(zLogger *zap.Logger) *Handler {
 
 	zapLogger := zLogger.With(zap.String("component", "handler"))
 
-	tdw := lexical.NewTextDocumentWatcher(c, lexical.NewPerformDiagnostics())
+	tdw := lexical.NewTextDocumentWatcher(c, lexical.NewPerformDiagnostics(c))
 
 	tracer, tracerCloser := initTracing("jsonnet-langauge-server", zapLogger)
 
@@ -180,17 +199,6 @@ func (lh *Handler) Handle(ctx context.Context, conn *jsonrpc2.Conn, req *jsonrpc
 	}
 }
 
-func completionItemResolve(ctx context.Context, r *request, c *config.Config) (interface{}, error) {
-	var ci lsp.CompletionItem
-	if err := r.Decode(&ci); err != nil {
-		return nil, err
-	}
-
-	// TODO figure out what do do here. This might not be needed and
-	// it can drop.
-	return nil, nil
-}
-
 func updateNodeCache(ctx context.Context, r *request, c *config.Config, uriStr string) {
 	span, ctx := tracing.ChildSpan(ctx, "updateNodeCache")
 	defer span.Finish()
@@ -309,10 +317,45 @@ func textDocumentDidSave(ctx context.Context, r *request, c *config.Config) (int
 	)
 
 	go updateNodeCache(ctx, r, c, dotdp.TextDocument.URI)
+	go updateWorkspaceIndexes(ctx, c, dotdp.TextDocument.URI)
 
 	return nil, nil
 }
 
+// updateWorkspaceIndexes indexes the symbols and imports in the saved text
+// of a document. It runs after the request has finished, so it has its own
+// span.
+func updateWorkspaceIndexes(ctx context.Context, c *config.Config, uriStr string) {
+	parent := opentracing.SpanFromContext(ctx)
+	span := parent.Tracer().StartSpan(
+		"updateWorkspaceIndexes",
+		opentracing.FollowsFrom(parent.Context()),
+	)
+	defer span.Finish()
+
+	ctx = opentracing.ContextWithSpan(ctx, span)
+
+	path, err := uri.ToPath(uriStr)
+	if err != nil {
+		span.LogFields(log.Error(err))
+		return
+	}
+
+	doc, err := c.Text(ctx, uriStr)
+	if err != nil {
+		span.LogFields(log.Error(err))
+		return
+	}
+
+	if err := c.SymbolIndex().UpdateSource(path, doc.String()); err != nil {
+		span.LogFields(log.Error(err))
+	}
+
+	if err := c.ImportIndex().UpdateSource(path, doc.String(), c.JsonnetLibPaths()); err != nil {
+		span.LogFields(log.Error(err))
+	}
+}
+
 func textDocumentDidClose(ctx context.Context, r *request, c *config.Config) (interface{}, error) {
 	span := opentracing.SpanFromContext(ctx)
 
@@ -325,6 +368,7 @@ func textDocumentDidClose(ctx context.Context, r *request, c *config.Config) (in
 		log.String("uri", params.TextDocument.URI),
 	)
 
+	c.SemanticTokens().Remove(params.TextDocument.URI)
 	go closeFile(ctx, c, params.TextDocument.URI)
 
 	return nil, nil
@@ -2,6 +2,10 @@ package server
 
 import (
 	"context"
+	"fmt"
+	"path/filepath"
+	"strconv"
+	"strings"
 
 	"github.com/tminor/jsonnet-language-server/pkg/analysis/lexical/token"
 	"github.com/tminor/jsonnet-language-server/pkg/util/position"
@@ -68,24 +72,252 @@ func (h *hover) handle(ctx context.Context) (interface{}, error) {
 		return nil, err
 	}
 
-	item, err := token.Identify(text.String(), pos, h.config.NodeCache(), ic)
+	d, err := token.Describe(text.String(), pos, h.config.NodeCache(), ic)
 	if err != nil {
 		return nil, err
 	}
 
-	value := item.String()
-	if value == "" {
+	if d.IsEmpty() {
 		return emptyHover, nil
 	}
 
+	markdown := false
+	for _, kind := range h.config.ClientCapabilities().TextDocument.Hover.ContentFormat {
+		if kind == lsp.MKMarkdown {
+			markdown = true
+			break
+		}
+	}
+
 	response := &lsp.Hover{
-		Contents: []lsp.MarkedString{
-			{
-				Language: "jsonnet",
-				Value:    value,
-			},
-		},
+		Contents: hoverContents(d, markdown),
 	}
 
 	return response, nil
 }
+
+// hoverContents creates hover contents for a description. Clients which
+// support markdown get the declaration, documentation, location and value
+// in a single document.
+func hoverContents(d *token.Description, markdown bool) interface{} {
+	if d.Import != nil {
+		return importHoverContents(d.Import, markdown)
+	}
+
+	if d.Object != nil {
+		return objectHoverContents(d.Object, markdown)
+	}
+
+	declaration := d.Declaration
+	if declaration == "" {
+		declaration = d.Static
+	}
+
+	if !markdown {
+		contents := []lsp.MarkedString{
+			{Language: "jsonnet", Value: declaration},
+		}
+
+		if len(d.Provenance) > 0 {
+			contents = append(contents, lsp.MarkedString{Value: provenance(d.Provenance, false)})
+		}
+
+		if d.Value != "" {
+			contents = append(contents, lsp.MarkedString{Language: "json", Value: d.Value})
+		}
+
+		return contents
+	}
+
+	var parts []string
+
+	parts = append(parts, fmt.Sprintf("```jsonnet\n%s\n```", declaration))
+
+	if d.Documentation != "" {
+		parts = append(parts, d.Documentation)
+	}
+
+	if d.Location != nil {
+		parts = append(parts, fmt.Sprintf("Defined in %s", locationLink(d.Location, true)))
+	}
+
+	if len(d.Provenance) > 0 {
+		parts = append(parts, provenance(d.Provenance, true))
+	}
+
+	if d.Value != "" {
+		parts = append(parts, fmt.Sprintf("```json\n%s\n```", d.Value))
+	}
+
+	return lsp.MarkupContent{
+		Kind:  lsp.MKMarkdown,
+		Value: strings.Join(parts, "\n\n"),
+	}
+}
+
+const (
+	// maxHoverFields limits the number of fields summarized in an import
+	// or object hover.
+	maxHoverFields = 30
+)
+
+// importHoverContents creates hover contents for an import. It shows where
+// the import was resolved, lib paths it shadows, and the imported fields.
+func importHoverContents(id *token.ImportDescription, markdown bool) interface{} {
+	var resolution []string
+	if markdown {
+		resolution = append(resolution, fmt.Sprintf("Resolved to [%s](%s)", id.Path, uri.FromPath(id.Path)))
+	} else {
+		resolution = append(resolution, fmt.Sprintf("Resolved to %s", id.Path))
+	}
+	resolution = append(resolution, fmt.Sprintf("Found in %s", id.LibPath))
+	if len(id.Shadowed) > 0 {
+		resolution = append(resolution, fmt.Sprintf("Shadows %s", strings.Join(id.Shadowed, ", ")))
+	}
+
+	summary := fieldSummary(id.Fields)
+
+	if !markdown {
+		var contents []lsp.MarkedString
+		if summary != "" {
+			contents = append(contents, lsp.MarkedString{Language: "jsonnet", Value: summary})
+		}
+
+		contents = append(contents, lsp.MarkedString{Value: strings.Join(resolution, "\n")})
+
+		if id.Documentation != "" {
+			contents = append(contents, lsp.MarkedString{Value: id.Documentation})
+		}
+
+		return contents
+	}
+
+	var parts []string
+
+	if summary != "" {
+		parts = append(parts, fmt.Sprintf("```jsonnet\n%s\n```", summary))
+	}
+
+	if id.Documentation != "" {
+		parts = append(parts, id.Documentation)
+	}
+
+	parts = append(parts, strings.Join(resolution, "  \n"))
+
+	return lsp.MarkupContent{
+		Kind:  lsp.MKMarkdown,
+		Value: strings.Join(parts, "\n\n"),
+	}
+}
+
+// objectHoverContents creates hover contents for `self`, `super` or `$`. It
+// shows the object's fields and the objects it is composed of.
+func objectHoverContents(od *token.ObjectDescription, markdown bool) interface{} {
+	summary := fieldSummary(od.Fields)
+	if summary == "" {
+		summary = "{}"
+	}
+
+	var chain []string
+	for i := range od.Chain {
+		chain = append(chain, locationLink(&od.Chain[i], markdown))
+	}
+
+	var resolution []string
+	if od.Location != nil {
+		resolution = append(resolution, fmt.Sprintf("`%s` in object at %s", od.Keyword, locationLink(od.Location, markdown)))
+	}
+
+	switch {
+	case od.Keyword == "super" && len(chain) == 0:
+		resolution = append(resolution, "No base objects")
+	case od.Keyword == "super":
+		resolution = append(resolution, fmt.Sprintf("Base objects: %s", strings.Join(chain, " + ")))
+	case len(chain) > 1:
+		resolution = append(resolution, fmt.Sprintf("Composed of: %s", strings.Join(chain, " + ")))
+	}
+
+	if !markdown {
+		return []lsp.MarkedString{
+			{Language: "jsonnet", Value: summary},
+			{Value: strings.Join(resolution, "\n")},
+		}
+	}
+
+	parts := []string{
+		fmt.Sprintf("```jsonnet\n%s\n```", summary),
+		strings.Join(resolution, "  \n"),
+	}
+
+	return lsp.MarkupContent{
+		Kind:  lsp.MKMarkdown,
+		Value: strings.Join(parts, "\n\n"),
+	}
+}
+
+// provenance lists the definitions of a field in the order they are
+// applied. Definitions which are replaced by a later definition are marked.
+func provenance(definitions []token.FieldDefinition, markdown bool) string {
+	lines := []string{"Definitions:"}
+	for i := range definitions {
+		fd := definitions[i]
+
+		operator := fd.Operator
+		if markdown {
+			operator = fmt.Sprintf("`%s`", operator)
+		}
+
+		line := fmt.Sprintf("%d. %s %s %s", i+1, locationLink(&fd.Location, markdown), operator, fd.Static)
+		if fd.Overridden {
+			line += " (overridden)"
+		}
+
+		lines = append(lines, line)
+	}
+
+	return strings.Join(lines, "\n")
+}
+
+// locationLink describes a location as file:line. In markdown, it links to
+// the location.
+func locationLink(loc *position.Location, markdown bool) string {
+	r := loc.Range()
+	text := fmt.Sprintf("%s:%d", filepath.Base(loc.URI()), r.Start.Line())
+	if !markdown {
+		return text
+	}
+
+	lspLoc := loc.ToLSP()
+	return fmt.Sprintf("[%s](%s#L%d)", text, lspLoc.URI, r.Start.Line())
+}
+
+// fieldSummary summarizes fields as an object.
+func fieldSummary(fields []token.Field) string {
+	if len(fields) == 0 {
+		return ""
+	}
+
+	lines := []string{"{"}
+	for i := range fields {
+		if i == maxHoverFields {
+			lines = append(lines, "  ...")
+			break
+		}
+
+		field := fields[i]
+
+		name := field.Name
+		if !reIdentifier.MatchString(name) {
+			name = strconv.Quote(name)
+		}
+
+		if field.IsFunction() {
+			name += "()"
+		}
+
+		lines = append(lines, fmt.Sprintf("  %s%s ...,", name, field.Visibility()))
+	}
+	lines = append(lines, "}")
+
+	return strings.Join(lines, "\n")
+}
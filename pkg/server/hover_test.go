@@ -0,0 +1,188 @@
+package server
+
+import (
+	"testing"
+
+	"github.com/tminor/jsonnet-language-server/pkg/analysis/lexical/token"
+	"github.com/tminor/jsonnet-language-server/pkg/lsp"
+	"github.com/tminor/jsonnet-language-server/pkg/util/position"
+	"github.com/google/go-jsonnet/ast"
+	"github.com/stretchr/testify/assert"
+)
+
+func Test_hoverContents(t *testing.T) {
+	loc := position.NewLocation("/app/lib.libsonnet", position.NewRangeFromCoords(3, 7, 3, 8))
+
+	cases := []struct {
+		name     string
+		d        *token.Description
+		markdown bool
+		expected interface{}
+	}{
+		{
+			name: "markdown",
+			d: &token.Description{
+				Static:        "(number) 42",
+				Declaration:   "local a = 42;",
+				Documentation: "the answer",
+				Location:      &loc,
+				Value:         "42",
+			},
+			markdown: true,
+			expected: lsp.MarkupContent{
+				Kind: lsp.MKMarkdown,
+				Value: "```jsonnet\nlocal a = 42;\n```\n\nthe answer\n\n" +
+					"Defined in [lib.libsonnet:3](file:///app/lib.libsonnet#L3)\n\n```json\n42\n```",
+			},
+		},
+		{
+			name: "markdown static fallback",
+			d: &token.Description{
+				Static: "(function)",
+			},
+			markdown: true,
+			expected: lsp.MarkupContent{
+				Kind:  lsp.MKMarkdown,
+				Value: "```jsonnet\n(function)\n```",
+			},
+		},
+		{
+			name: "import",
+			d: &token.Description{
+				Declaration: `local lib = import "lib.libsonnet";`,
+				Import: &token.ImportDescription{
+					Name:          "lib.libsonnet",
+					Path:          "/lib1/lib.libsonnet",
+					LibPath:       "/lib1",
+					Shadowed:      []string{"/lib2"},
+					Fields:        []token.Field{{Name: "a", Hide: ast.ObjectFieldInherit}, {Name: "b-c", Hide: ast.ObjectFieldHidden}},
+					Documentation: "lib header",
+				},
+			},
+			markdown: true,
+			expected: lsp.MarkupContent{
+				Kind: lsp.MKMarkdown,
+				Value: "```jsonnet\n{\n  a: ...,\n  \"b-c\":: ...,\n}\n```\n\nlib header\n\n" +
+					"Resolved to [/lib1/lib.libsonnet](file:///lib1/lib.libsonnet)  \nFound in /lib1  \nShadows /lib2",
+			},
+		},
+		{
+			name: "import path with spaces",
+			d: &token.Description{
+				Import: &token.ImportDescription{
+					Name:    "a#b.libsonnet",
+					Path:    "/lib 1/a#b.libsonnet",
+					LibPath: "/lib 1",
+				},
+			},
+			markdown: true,
+			expected: lsp.MarkupContent{
+				Kind:  lsp.MKMarkdown,
+				Value: "Resolved to [/lib 1/a#b.libsonnet](file:///lib%201/a%23b.libsonnet)  \nFound in /lib 1",
+			},
+		},
+		{
+			name: "super",
+			d: &token.Description{
+				Static: "super",
+				Object: &token.ObjectDescription{
+					Keyword:  "super",
+					Location: &loc,
+					Fields:   []token.Field{{Name: "a", Hide: ast.ObjectFieldInherit}, {Name: "b", Hide: ast.ObjectFieldHidden}},
+					Chain:    []position.Location{loc, loc},
+				},
+			},
+			markdown: true,
+			expected: lsp.MarkupContent{
+				Kind: lsp.MKMarkdown,
+				Value: "```jsonnet\n{\n  a: ...,\n  b:: ...,\n}\n```\n\n" +
+					"`super` in object at [lib.libsonnet:3](file:///app/lib.libsonnet#L3)  \n" +
+					"Base objects: [lib.libsonnet:3](file:///app/lib.libsonnet#L3) + [lib.libsonnet:3](file:///app/lib.libsonnet#L3)",
+			},
+		},
+		{
+			name: "self plain text",
+			d: &token.Description{
+				Static: "self",
+				Object: &token.ObjectDescription{
+					Keyword:  "self",
+					Location: &loc,
+				},
+			},
+			expected: []lsp.MarkedString{
+				{Language: "jsonnet", Value: "{}"},
+				{Value: "`self` in object at lib.libsonnet:3"},
+			},
+		},
+		{
+			name: "provenance",
+			d: &token.Description{
+				Static:      "(number) 3",
+				Declaration: "replicas: 3,",
+				Location:    &loc,
+				Provenance: []token.FieldDefinition{
+					{Location: loc, Operator: ":", Static: "(number) 1"},
+					{Location: loc, Operator: "+:", Static: "(number) 2"},
+				},
+				Value: "3",
+			},
+			markdown: true,
+			expected: lsp.MarkupContent{
+				Kind: lsp.MKMarkdown,
+				Value: "```jsonnet\nreplicas: 3,\n```\n\n" +
+					"Defined in [lib.libsonnet:3](file:///app/lib.libsonnet#L3)\n\n" +
+					"Definitions:\n" +
+					"1. [lib.libsonnet:3](file:///app/lib.libsonnet#L3) `:` (number) 1\n" +
+					"2. [lib.libsonnet:3](file:///app/lib.libsonnet#L3) `+:` (number) 2\n\n" +
+					"```json\n3\n```",
+			},
+		},
+		{
+			name: "provenance plain text",
+			d: &token.Description{
+				Static:      "(number) 3",
+				Declaration: "replicas: 3,",
+				Provenance: []token.FieldDefinition{
+					{Location: loc, Operator: "+:", Static: "(number) 2"},
+				},
+			},
+			expected: []lsp.MarkedString{
+				{Language: "jsonnet", Value: "replicas: 3,"},
+				{Value: "Definitions:\n1. lib.libsonnet:3 +: (number) 2"},
+			},
+		},
+		{
+			name: "provenance overridden",
+			d: &token.Description{
+				Static:      "(number) 3",
+				Declaration: "replicas: 3,",
+				Provenance: []token.FieldDefinition{
+					{Location: loc, Operator: ":", Static: "(number) 1", Overridden: true},
+					{Location: loc, Operator: ":", Static: "(number) 3"},
+				},
+			},
+			expected: []lsp.MarkedString{
+				{Language: "jsonnet", Value: "replicas: 3,"},
+				{Value: "Definitions:\n1. lib.libsonnet:3 : (number) 1 (overridden)\n2. lib.libsonnet:3 : (number) 3"},
+			},
+		},
+		{
+			name: "plain text",
+			d: &token.Description{
+				Static:      "(number) 42",
+				Declaration: "local a = 42;",
+				Value:       "42",
+			},
+			expected: []lsp.MarkedString{
+				{Language: "jsonnet", Value: "local a = 42;"},
+				{Language: "json", Value: "42"},
+			},
+		},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			assert.Equal(t, tc.expected, hoverContents(tc.d, tc.markdown))
+		})
+	}
+}
@@ -0,0 +1,79 @@
+package server
+
+import (
+	"context"
+
+	"github.com/tminor/jsonnet-language-server/pkg/analysis/lexical/token"
+	"github.com/tminor/jsonnet-language-server/pkg/config"
+	"github.com/tminor/jsonnet-language-server/pkg/lsp"
+	jpos "github.com/tminor/jsonnet-language-server/pkg/util/position"
+	"github.com/tminor/jsonnet-language-server/pkg/util/uri"
+	opentracing "github.com/opentracing/opentracing-go"
+	"github.com/opentracing/opentracing-go/log"
+)
+
+// textDocumentImplementation navigates between the declarations of a field
+// in a composed object. It returns the declarations the field overrides or
+// merges with, followed by the declarations which override it in the
+// current file and the files which import it.
+func textDocumentImplementation(ctx context.Context, r *request, c *config.Config) (interface{}, error) {
+	span := opentracing.SpanFromContext(ctx)
+	ctx = opentracing.ContextWithSpan(ctx, span)
+
+	var params lsp.TextDocumentPositionParams
+	if err := r.Decode(&params); err != nil {
+		return nil, err
+	}
+
+	doc, err := c.Text(ctx, params.TextDocument.URI)
+	if err != nil {
+		return nil, err
+	}
+
+	path, err := uri.ToPath(params.TextDocument.URI)
+	if err != nil {
+		return nil, err
+	}
+
+	pos := jpos.FromLSPPosition(params.Position)
+
+	is, err := token.Implementations(ctx, path, doc.String(), pos, c.NodeCache(), c.JsonnetLibPaths())
+	if err != nil {
+		return nil, err
+	}
+
+	if is.Declaration.URI() == "" {
+		return []lsp.Location{}, nil
+	}
+
+	var locations []jpos.Location
+	locations = append(locations, is.Bases...)
+	locations = append(locations, is.Overrides...)
+
+	for _, filename := range dependentFiles(c, []jpos.Location{is.Declaration}) {
+		if filename == path {
+			continue
+		}
+
+		if err := ctx.Err(); err != nil {
+			return nil, err
+		}
+
+		fileDoc, err := c.Text(ctx, uri.FromPath(filename))
+		if err != nil {
+			span.LogFields(log.Error(err))
+			continue
+		}
+
+		overrides, err := token.FileOverrides(ctx, filename, fileDoc.String(), is.Declaration,
+			c.NodeCache(), c.JsonnetLibPaths())
+		if err != nil {
+			span.LogFields(log.Error(err))
+			continue
+		}
+
+		locations = append(locations, overrides...)
+	}
+
+	return referenceLocations(locations), nil
+}
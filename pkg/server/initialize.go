@@ -38,11 +38,18 @@ func initialize(ctx context.Context, r *request, c *config.Config) (interface{},
 			Watchers: make([]lsp.FileSystemWatcher, 0),
 		}
 
-		for _, path := range paths {
+		// the workspace is watched as well so its symbols can be
+		// indexed.
+		watched := paths
+		if ip.RootPath != "" {
+			watched = append([]string{ip.RootPath}, paths...)
+		}
+
+		for _, path := range watched {
 			path = filepath.Clean(path)
 			for _, ext := range []string{"libsonnet", "jsonnet"} {
 				watcher := lsp.FileSystemWatcher{
-					GlobPattern: filepath.Join(path, "*."+ext),
+					GlobPattern: filepath.Join(path, "**", "*."+ext),
 					Kind:        lsp.WatchKindChange + lsp.WatchKindCreate + lsp.WatchKindDelete,
 				}
 
@@ -54,10 +61,13 @@ func initialize(ctx context.Context, r *request, c *config.Config) (interface{},
 			span.LogFields(log.Error(err))
 		}
 
+		indexWorkspace(ctx, c, paths...)
+
 		return nil
 	}
 
 	c.Watch(config.JsonnetLibPaths, fn)
+	c.SetClientCapabilities(ip.Capabilities)
 
 	update, ok := ip.InitializationOptions.(map[string]interface{})
 	if !ok {
@@ -68,6 +78,8 @@ func initialize(ctx context.Context, r *request, c *config.Config) (interface{},
 		return nil, err
 	}
 
+	indexWorkspace(ctx, c, ip.RootPath)
+
 	span.LogFields(
 		log.String("workspace", ip.RootPath),
 		log.String("config", c.String()),
@@ -75,17 +87,31 @@ func initialize(ctx context.Context, r *request, c *config.Config) (interface{},
 
 	response := &lsp.InitializeResult{
 		Capabilities: lsp.ServerCapabilities{
+			CallHierarchyProvider: true,
+			TypeHierarchyProvider: true,
+			CodeActionProvider:    true,
+			CodeLensProvider:      &lsp.CodeLensOptions{},
 			CompletionProvider: &lsp.CompletionOptions{
 				ResolveProvider: true,
 			},
+			DocumentLinkProvider:      &lsp.DocumentLinkOptions{},
 			DocumentSymbolProvider:    true,
 			DocumentHighlightProvider: true,
+			FoldingRangeProvider:      true,
+			ImplementationProvider:    true,
 			HoverProvider:             true,
+			InlayHintProvider:         true,
 			ReferencesProvider:        true,
 			SignatureHelpProvider: &lsp.SignatureHelpOptions{
 				TriggerCharacters: []string{"("},
 			},
-			TextDocumentSync: lsp.TDSKFull,
+			ExecuteCommandProvider: &lsp.ExecuteCommandOptions{
+				Commands: []string{commandCompletionAccepted, commandEvaluate},
+			},
+			SelectionRangeProvider:  true,
+			SemanticTokensProvider:  semanticTokensOptions,
+			TextDocumentSync:        lsp.TDSKFull,
+			WorkspaceSymbolProvider: true,
 		},
 	}
 
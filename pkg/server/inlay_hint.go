@@ -0,0 +1,73 @@
+package server
+
+import (
+	"context"
+
+	"github.com/tminor/jsonnet-language-server/pkg/analysis/lexical/token"
+	"github.com/tminor/jsonnet-language-server/pkg/config"
+	"github.com/tminor/jsonnet-language-server/pkg/lsp"
+	jpos "github.com/tminor/jsonnet-language-server/pkg/util/position"
+	"github.com/tminor/jsonnet-language-server/pkg/util/uri"
+	opentracing "github.com/opentracing/opentracing-go"
+)
+
+func textDocumentInlayHint(ctx context.Context, r *request, c *config.Config) (interface{}, error) {
+	span := opentracing.SpanFromContext(ctx)
+	ctx = opentracing.ContextWithSpan(ctx, span)
+
+	var params lsp.InlayHintParams
+	if err := r.Decode(&params); err != nil {
+		return nil, err
+	}
+
+	doc, err := c.Text(ctx, params.TextDocument.URI)
+	if err != nil {
+		return nil, err
+	}
+
+	path, err := uri.ToPath(params.TextDocument.URI)
+	if err != nil {
+		return nil, err
+	}
+
+	ic, err := token.NewIdentifyConfig(path, c.JsonnetLibPaths()...)
+	if err != nil {
+		return nil, err
+	}
+
+	hintRange := jpos.NewRange(
+		jpos.FromLSPPosition(params.Range.Start),
+		jpos.FromLSPPosition(params.Range.End))
+
+	hints, err := token.InlayHints(doc.String(), hintRange, c.InlayHints(), c.NodeCache(), ic)
+	if err != nil {
+		return nil, err
+	}
+
+	return inlayHints(hints), nil
+}
+
+// inlayHints converts inlay hints to LSP inlay hints. Parameter names are
+// shown before arguments and values are shown after local binds.
+func inlayHints(hints []token.InlayHint) []lsp.InlayHint {
+	out := []lsp.InlayHint{}
+
+	for _, hint := range hints {
+		ih := lsp.InlayHint{
+			Position: hint.Position.ToLSP(),
+			Label:    hint.Label,
+		}
+
+		switch hint.Kind {
+		case token.InlayHintParameter:
+			ih.Kind = lsp.IHKParameter
+			ih.PaddingRight = true
+		case token.InlayHintValue:
+			ih.PaddingLeft = true
+		}
+
+		out = append(out, ih)
+	}
+
+	return out
+}
@@ -0,0 +1,33 @@
+package server
+
+import (
+	"testing"
+
+	"github.com/tminor/jsonnet-language-server/pkg/analysis/lexical/token"
+	"github.com/tminor/jsonnet-language-server/pkg/lsp"
+	jpos "github.com/tminor/jsonnet-language-server/pkg/util/position"
+	"github.com/stretchr/testify/assert"
+)
+
+func Test_inlayHints(t *testing.T) {
+	hints := []token.InlayHint{
+		{Position: jpos.New(2, 3), Label: "a:", Kind: token.InlayHintParameter},
+		{Position: jpos.New(3, 16), Label: "= 3", Kind: token.InlayHintValue},
+	}
+
+	expected := []lsp.InlayHint{
+		{
+			Position:     lsp.Position{Line: 1, Character: 2},
+			Label:        "a:",
+			Kind:         lsp.IHKParameter,
+			PaddingRight: true,
+		},
+		{
+			Position:    lsp.Position{Line: 2, Character: 15},
+			Label:       "= 3",
+			PaddingLeft: true,
+		},
+	}
+
+	assert.Equal(t, expected, inlayHints(hints))
+}
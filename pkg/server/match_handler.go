@@ -2,26 +2,27 @@ package server
 
 import (
 	"context"
-	"fmt"
+	"path/filepath"
 	"regexp"
 	"strings"
 
 	"github.com/opentracing/opentracing-go"
 	"github.com/opentracing/opentracing-go/log"
 
-	"github.com/tminor/jsonnet-language-server/pkg/analysis/lexical/astext"
 	"github.com/tminor/jsonnet-language-server/pkg/analysis/lexical/token"
 	"github.com/tminor/jsonnet-language-server/pkg/config"
 	"github.com/tminor/jsonnet-language-server/pkg/langserver"
 	"github.com/tminor/jsonnet-language-server/pkg/lsp"
 	"github.com/tminor/jsonnet-language-server/pkg/util/position"
-	"github.com/tminor/jsonnet-language-server/pkg/util/text"
-	"github.com/google/go-jsonnet/ast"
 	"github.com/pkg/errors"
 )
 
 type jsonnetPathManager interface {
-	Files() ([]string, error)
+	// LibPaths returns the Jsonnet lib paths.
+	LibPaths() []string
+	// Candidates returns files and directories in dir relative to the
+	// importing file and the lib paths.
+	Candidates(importer, dir string, filter func(string) bool) ([]langserver.ImportCandidate, error)
 }
 
 type defaultJsonnetPathManager struct {
@@ -34,9 +35,14 @@ func newJsonnetPathManager(c *config.Config) *defaultJsonnetPathManager {
 	}
 }
 
-func (jpm *defaultJsonnetPathManager) Files() ([]string, error) {
-	lp := langserver.NewLibPaths(jpm.config.JsonnetLibPaths())
-	return lp.Files()
+func (jpm *defaultJsonnetPathManager) LibPaths() []string {
+	return jpm.config.JsonnetLibPaths()
+}
+
+func (jpm *defaultJsonnetPathManager) Candidates(importer, dir string, filter func(string) bool) ([]langserver.ImportCandidate, error) {
+	paths := append([]string{filepath.Dir(importer)}, jpm.config.JsonnetLibPaths()...)
+	lp := langserver.NewLibPaths(paths)
+	return lp.Candidates(dir, filter)
 }
 
 type matchHandler struct {
@@ -54,40 +60,92 @@ func newMatchHandler(jpm jsonnetPathManager, nc *token.NodeCache) *matchHandler
 }
 
 func (mh *matchHandler) register(cm *langserver.CompletionMatcher) error {
-	m := map[string]langserver.CompletionAction{
-		`import\s`:    mh.handleImport,
-		`importstr\s`: mh.handleImport,
-		`\w+\.`:       mh.handleIndex,
-	}
-
-	for term, fn := range m {
-		if err := cm.Register(term, fn); err != nil {
-			return errors.Wrapf(err, "registering completion matcher %q", term)
+	// terms are matched in order, so imports must be matched before indexes
+	// since import paths contain dots. Terms are anchored at the cursor
+	// when they are registered. The import term is also anchored before
+	// closing characters, so an import which has been completed doesn't
+	// match.
+	m := []struct {
+		term string
+		fn   langserver.CompletionAction
+	}{
+		{term: `import(str)?\s+(["'][^"'\n]*["']?)?$`, fn: mh.handleImport},
+		{term: `[\w\)\]]\.`, fn: mh.handleIndex},
+	}
+
+	for _, item := range m {
+		if err := cm.Register(item.term, item.fn); err != nil {
+			return errors.Wrapf(err, "registering completion matcher %q", item.term)
 		}
 	}
 
 	return nil
 }
 
+var (
+	// the truncated source can include a closing quote after the position.
+	reImport = regexp.MustCompile(`(import|importstr)\s+(["']?)([^"'\n]*)["']?$`)
+)
+
 func (mh *matchHandler) handleImport(ctx context.Context, pos position.Position, path, source string) ([]lsp.CompletionItem, error) {
 	span := opentracing.SpanFromContext(ctx)
 	span.LogFields(
 		log.String("match.type", "import"),
 	)
 
-	editRange := position.NewRange(pos, pos)
-	var items []lsp.CompletionItem
+	// the import must be on the line being completed. The source is
+	// truncated at the position, so an import on an earlier line ends it
+	// when the cursor is on an empty line.
+	match := reImport.FindStringSubmatch(source)
+	if match == nil || strings.Count(source, "\n")+1 != pos.Line() {
+		return nil, nil
+	}
+
+	keyword, quote, typed := match[1], match[2], match[3]
+
+	filter := langserver.IsImportable
+	if keyword == "importstr" {
+		filter = func(string) bool { return true }
+	}
+
+	// only the segment after the last slash is replaced.
+	dir, segment := "", typed
+	if i := strings.LastIndex(typed, "/"); i >= 0 {
+		dir, segment = typed[:i+1], typed[i+1:]
+	}
+
+	start := position.New(pos.Line(), pos.Column()-len(segment))
+	if quote == "" {
+		start = position.New(pos.Line(), pos.Column()-len(typed))
+	}
+	editRange := position.NewRange(start, pos)
 
-	files, err := mh.jsonnetPathManager.Files()
+	candidates, err := mh.jsonnetPathManager.Candidates(path, dir, filter)
 	if err != nil {
 		return nil, err
 	}
 
-	for _, file := range files {
-		text := fmt.Sprintf(`"%s"`, file)
-		ci := createCompletionItem(file, text, lsp.CIKFile, editRange, nil)
-		items = append(items, ci)
+	var items []lsp.CompletionItem
+
+	for _, candidate := range candidates {
+		label := candidate.Name
+		kind := lsp.CIKFile
+		if candidate.IsDir {
+			label += "/"
+			kind = lsp.CIKFolder
+		}
 
+		text := label
+		if quote == "" {
+			text = `"` + dir + label
+			if !candidate.IsDir {
+				text += `"`
+			}
+		}
+
+		ci := createCompletionItem(label, text, kind, editRange,
+			&token.ScopeEntry{Detail: candidate.Path})
+		items = append(items, ci)
 	}
 
 	return items, nil
@@ -99,71 +157,36 @@ func (mh *matchHandler) handleIndex(ctx context.Context, pos position.Position,
 		log.String("match.type", "index"),
 	)
 
-	var items []lsp.CompletionItem
-
-	scope, err := token.LocationScope(filePath, source, pos, mh.nodeCache)
-	if err != nil {
-		return nil, err
-	}
-
-	truncated, err := text.Truncate(source, pos)
+	config, err := token.NewIdentifyConfig(filePath, mh.jsonnetPathManager.LibPaths()...)
 	if err != nil {
 		return nil, err
 	}
 
-	path, err := resolveIndex(truncated)
+	// the term can match text which isn't an index, e.g. a number, so
+	// there are no items when an index can't be found.
+	fields, err := token.IndexFields(source, pos, mh.nodeCache, config)
 	if err != nil {
-		return nil, err
-	}
-
-	se, err := scope.GetInPath(path)
-	if err != nil {
-		return nil, err
+		span.LogFields(
+			log.Error(err),
+		)
+		return nil, nil
 	}
 
 	editRange := position.NewRange(pos, pos)
 
-	switch n := se.Node.(type) {
-	case *ast.DesugaredObject:
-		for _, field := range n.Fields {
-			name := astext.TokenValue(field.Name)
-
-			fieldSe := &token.ScopeEntry{
-				Detail: astext.TokenName(field.Body),
-			}
+	var items []lsp.CompletionItem
+	for i := range fields {
+		field := fields[i]
 
-			ci := createCompletionItem(name, name, lsp.CIKVariable, editRange, fieldSe)
-			items = append(items, ci)
+		kind := lsp.CIKField
+		if field.IsFunction() {
+			kind = lsp.CIKMethod
 		}
-	case *ast.Object:
-		// TODO check to see if this case is used. Objects should be desguared.
-		for _, field := range n.Fields {
-			var name string
-			switch field.Kind {
-			case ast.ObjectFieldID:
-				if field.Id == nil {
-					return nil, errors.New("field id shouldn't be nil")
-				}
-				name = string(*field.Id)
-			case ast.ObjectFieldStr:
-				if field.Expr1 == nil {
-					return nil, errors.New("field id should be a string")
-				}
-				name = astext.TokenValue(field.Expr1)
-			}
-			if name != "" {
-				fieldSe := &token.ScopeEntry{
-					Detail: astext.TokenName(field.Expr2),
-				}
 
-				ci := createCompletionItem(name, name, lsp.CIKVariable, editRange, fieldSe)
-				items = append(items, ci)
-			}
-		}
-	default:
-		span.LogFields(
-			log.String("text.unhandled", fmt.Sprintf("%T", n)),
-		)
+		// details are computed when the item is resolved.
+		ci := createCompletionItem(field.Name, field.Name, kind, editRange, nil)
+		ci.Data = &completionResolve{Kind: resolveKindIndex, Name: field.Name}
+		items = append(items, ci)
 	}
 
 	return items, nil
@@ -188,40 +211,6 @@ func createCompletionItem(label, text string, kind int, r position.Range, se *to
 	}
 }
 
-var (
-	reIndex = regexp.MustCompile(`((\w+\.)*\w+)\.[;\]\)\}]*$`)
-)
-
-func resolveIndex(source string) ([]string, error) {
-	match := reIndex.FindAllString(source, 1)
-	if match == nil {
-		return nil, errors.Errorf("%q does not contain an index", source)
-	}
-
-	if len(match) != 1 {
-		return nil, errors.Errorf("expected only one match when looking for index")
-	}
-
-	return removeEmpty(strings.Split(match[0], ".")), nil
-}
-
-var (
-	ignoredIndexItems = []string{"}", "]", ")", ";"}
-)
-
-func removeEmpty(sl []string) []string {
-	var out []string
-	for _, s := range sl {
-		if s != "" {
-			if !stringInSlice(s, ignoredIndexItems) {
-				out = append(out, s)
-			}
-		}
-	}
-
-	return out
-}
-
 func stringInSlice(s string, sl []string) bool {
 	for i := range sl {
 		if sl[i] == s {
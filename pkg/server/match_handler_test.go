@@ -1,6 +1,7 @@
 package server
 
 import (
+	"context"
 	"testing"
 
 	"github.com/tminor/jsonnet-language-server/pkg/analysis/lexical/token"
@@ -12,25 +13,65 @@ import (
 )
 
 func Test_matchHandler_handleImport(t *testing.T) {
-	nc := token.NewNodeCache()
-	cm := langserver.NewCompletionMatcher()
+	candidates := map[string][]langserver.ImportCandidate{
+		"": {
+			{Name: "1.jsonnet", Path: "/lib"},
+			{Name: "2.libsonnet", Path: "/lib"},
+			{Name: "dir", IsDir: true, Path: "/app"},
+		},
+		"dir/": {
+			{Name: "3.libsonnet", Path: "/app"},
+		},
+	}
 
-	source := "local foo = {\n    a: \"b\"\n};\n\nlocal y = import "
+	cases := []struct {
+		name     string
+		source   string
+		pos      position.Position
+		expected func() []lsp.CompletionItem
+	}{
+		{
+			name:   "no path",
+			source: "local foo = {\n    a: \"b\"\n};\n\nlocal y = import ",
+			pos:    position.New(5, 18),
+			expected: func() []lsp.CompletionItem {
+				r := position.NewRangeFromCoords(5, 18, 5, 18)
+				return []lsp.CompletionItem{
+					createCompletionItem("1.jsonnet", `"1.jsonnet"`, lsp.CIKFile, r, &token.ScopeEntry{Detail: "/lib"}),
+					createCompletionItem("2.libsonnet", `"2.libsonnet"`, lsp.CIKFile, r, &token.ScopeEntry{Detail: "/lib"}),
+					createCompletionItem("dir/", `"dir/`, lsp.CIKFolder, r, &token.ScopeEntry{Detail: "/app"}),
+				}
+			},
+		},
+		{
+			name:   "partial path",
+			source: `local y = importstr "dir/3.`,
+			pos:    position.New(1, 28),
+			expected: func() []lsp.CompletionItem {
+				r := position.NewRangeFromCoords(1, 26, 1, 28)
+				return []lsp.CompletionItem{
+					createCompletionItem("3.libsonnet", "3.libsonnet", lsp.CIKFile, r, &token.ScopeEntry{Detail: "/app"}),
+				}
+			},
+		},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			nc := token.NewNodeCache()
+			cm := langserver.NewCompletionMatcher()
 
-	jpm := &fakeJsonnetPathManager{files: []string{"1.jsonnet", "2.libsonnet"}}
-	mh := newMatchHandler(jpm, nc)
-	mh.register(cm)
+			jpm := &fakeJsonnetPathManager{candidates: candidates}
+			mh := newMatchHandler(jpm, nc)
+			err := mh.register(cm)
+			require.NoError(t, err)
 
-	pos := position.New(5, 18)
-	got, err := cm.Match(pos, "file.jsonnet", source)
-	require.NoError(t, err)
+			got, err := cm.Match(context.Background(), tc.pos, "/app/file.jsonnet", tc.source)
+			require.NoError(t, err)
 
-	editRange := position.NewRange(pos, pos)
-	expected := []lsp.CompletionItem{
-		createCompletionItem("1.jsonnet", `"1.jsonnet"`, lsp.CIKFile, editRange, nil),
-		createCompletionItem("2.libsonnet", `"2.libsonnet"`, lsp.CIKFile, editRange, nil),
+			assert.Equal(t, tc.expected(), got)
+		})
 	}
-	assert.Equal(t, expected, got)
 }
 
 func Test_matchHandler_handleIndex(t *testing.T) {
@@ -46,8 +87,7 @@ func Test_matchHandler_handleIndex(t *testing.T) {
 			at:   position.New(5, 13),
 			expected: func(r position.Range) []lsp.CompletionItem {
 				return []lsp.CompletionItem{
-					createCompletionItem(`"a"`, `"a"`, lsp.CIKVariable, r,
-						&token.ScopeEntry{Detail: "(local)"}),
+					indexCompletionItem("a", lsp.CIKField, r),
 				}
 			},
 		},
@@ -57,8 +97,17 @@ func Test_matchHandler_handleIndex(t *testing.T) {
 			at:   position.New(1, 31),
 			expected: func(r position.Range) []lsp.CompletionItem {
 				return []lsp.CompletionItem{
-					createCompletionItem(`"a"`, `"a"`, lsp.CIKVariable, r,
-						&token.ScopeEntry{Detail: `(string) "a"`}),
+					indexCompletionItem("a", lsp.CIKField, r),
+				}
+			},
+		},
+		{
+			name: "call",
+			text: `local f(x)={a:x};f(1).`,
+			at:   position.New(1, 23),
+			expected: func(r position.Range) []lsp.CompletionItem {
+				return []lsp.CompletionItem{
+					indexCompletionItem("a", lsp.CIKField, r),
 				}
 			},
 		},
@@ -69,11 +118,11 @@ func Test_matchHandler_handleIndex(t *testing.T) {
 			nc := token.NewNodeCache()
 			cm := langserver.NewCompletionMatcher()
 
-			jpm := &fakeJsonnetPathManager{files: []string{"1.jsonnet", "2.libsonnet"}}
+			jpm := &fakeJsonnetPathManager{}
 			mh := newMatchHandler(jpm, nc)
 			mh.register(cm)
 
-			got, err := cm.Match(tc.at, "file.jsonnet", tc.text)
+			got, err := cm.Match(context.Background(), tc.at, "file.jsonnet", tc.text)
 			require.NoError(t, err)
 
 			editRange := position.NewRange(tc.at, tc.at)
@@ -82,47 +131,31 @@ func Test_matchHandler_handleIndex(t *testing.T) {
 	}
 }
 
-func OffTest_resolveIndex(t *testing.T) {
-	cases := []struct {
-		name     string
-		in       string
-		expected []string
-		isErr    bool
-	}{
-		{
-			name:     "incomplete short index",
-			in:       "data.",
-			expected: []string{"data"},
-		},
-		{
-			name:     "incomplete longer index",
-			in:       "o.data.",
-			expected: []string{"o", "data"},
-		},
-	}
-
-	for _, tc := range cases {
-		t.Run(tc.name, func(t *testing.T) {
-			got, err := resolveIndex(tc.in)
-			if tc.isErr {
-				require.Error(t, err)
-				return
-			}
-
-			if assert.NoError(t, err) {
-				assert.Equal(t, tc.expected, got)
-			}
-		})
-	}
+func indexCompletionItem(name string, kind int, r position.Range) lsp.CompletionItem {
+	ci := createCompletionItem(name, name, kind, r, nil)
+	ci.Data = &completionResolve{Kind: resolveKindIndex, Name: name}
+	return ci
 }
 
 type fakeJsonnetPathManager struct {
-	files    []string
-	filesErr error
+	libPaths      []string
+	candidates    map[string][]langserver.ImportCandidate
+	candidatesErr error
 }
 
 var _ jsonnetPathManager = (*fakeJsonnetPathManager)(nil)
 
-func (jpm *fakeJsonnetPathManager) Files() ([]string, error) {
-	return jpm.files, jpm.filesErr
+func (jpm *fakeJsonnetPathManager) LibPaths() []string {
+	return jpm.libPaths
+}
+
+func (jpm *fakeJsonnetPathManager) Candidates(importer, dir string, filter func(string) bool) ([]langserver.ImportCandidate, error) {
+	var out []langserver.ImportCandidate
+	for _, candidate := range jpm.candidates[dir] {
+		if candidate.IsDir || filter(candidate.Name) {
+			out = append(out, candidate)
+		}
+	}
+
+	return out, jpm.candidatesErr
 }
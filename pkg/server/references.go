@@ -9,6 +9,7 @@ import (
 	jpos "github.com/tminor/jsonnet-language-server/pkg/util/position"
 	"github.com/tminor/jsonnet-language-server/pkg/util/uri"
 	opentracing "github.com/opentracing/opentracing-go"
+	"github.com/opentracing/opentracing-go/log"
 )
 
 func textDocumentReferences(ctx context.Context, r *request, c *config.Config) (interface{}, error) {
@@ -32,15 +33,161 @@ func textDocumentReferences(ctx context.Context, r *request, c *config.Config) (
 
 	pos := jpos.FromLSPPosition(params.Position)
 
-	locations, err := token.Highlight(ctx, path, doc.String(), pos, c.NodeCache())
+	rs, err := token.References(ctx, path, doc.String(), pos, c.NodeCache(), c.JsonnetLibPaths())
 	if err != nil {
 		return nil, err
 	}
 
-	var lspLocations []lsp.Location
-	for _, l := range locations.Slice() {
-		lspLocations = append(lspLocations, l.ToLSP())
+	lspLocations := []lsp.Location{}
+	report := func(locations []jpos.Location) error {
+		lspLocations = append(lspLocations, referenceLocations(locations)...)
+		return nil
+	}
+
+	// results for files importing the declarations are streamed to clients
+	// which support partial results.
+	if params.PartialResultToken != nil {
+		report = func(locations []jpos.Location) error {
+			progress := &lsp.ProgressParams{
+				Token: params.PartialResultToken,
+				Value: referenceLocations(locations),
+			}
+
+			return r.conn.Notify(ctx, "$/progress", progress)
+		}
+	}
+
+	wr := &workspaceReferences{
+		config:             c,
+		includeDeclaration: params.Context.IncludeDeclaration,
+		report:             report,
+		seen:               make(map[jpos.Location]bool),
+	}
+
+	if err := wr.find(ctx, path, rs); err != nil {
+		return nil, err
 	}
 
 	return lspLocations, nil
 }
+
+// workspaceReferences finds references in the files which import the files
+// an identifier is declared in.
+type workspaceReferences struct {
+	config             *config.Config
+	includeDeclaration bool
+	report             func([]jpos.Location) error
+	seen               map[jpos.Location]bool
+}
+
+// find reports the references found in the current file, then searches the
+// files the identifier is declared in and the files which import them,
+// directly or transitively. Locals and parameters can only be referenced in
+// the current file.
+func (wr *workspaceReferences) find(ctx context.Context, path string, rs token.ReferenceSet) error {
+	span := opentracing.SpanFromContext(ctx)
+
+	if err := wr.add(rs); err != nil {
+		return err
+	}
+
+	if rs.Local || len(rs.Declarations) == 0 {
+		return nil
+	}
+
+	for _, filename := range wr.files(path, rs.Declarations) {
+		if err := ctx.Err(); err != nil {
+			return err
+		}
+
+		doc, err := wr.config.Text(ctx, uri.FromPath(filename))
+		if err != nil {
+			span.LogFields(log.Error(err))
+			continue
+		}
+
+		frs, err := token.FileReferences(ctx, filename, doc.String(), rs.Declarations,
+			wr.config.NodeCache(), wr.config.JsonnetLibPaths())
+		if err != nil {
+			span.LogFields(log.Error(err))
+			continue
+		}
+
+		if err := wr.add(frs); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// files returns the files to search for references other than the current
+// file.
+func (wr *workspaceReferences) files(path string, declarations []jpos.Location) []string {
+	var files []string
+	for _, filename := range dependentFiles(wr.config, declarations) {
+		if filename != path {
+			files = append(files, filename)
+		}
+	}
+
+	return files
+}
+
+// add reports the locations in a reference set which haven't been reported.
+func (wr *workspaceReferences) add(rs token.ReferenceSet) error {
+	var locations []jpos.Location
+	if wr.includeDeclaration {
+		locations = append(locations, rs.Declarations...)
+	}
+	locations = append(locations, rs.References...)
+
+	var unseen []jpos.Location
+	for _, l := range locations {
+		if !wr.seen[l] {
+			wr.seen[l] = true
+			unseen = append(unseen, l)
+		}
+	}
+
+	if len(unseen) == 0 {
+		return nil
+	}
+
+	return wr.report(unseen)
+}
+
+func referenceLocations(locations []jpos.Location) []lsp.Location {
+	out := make([]lsp.Location, 0, len(locations))
+	for _, l := range locations {
+		out = append(out, l.ToLSP())
+	}
+
+	return out
+}
+
+// dependentFiles returns the files declarations are in, followed by the
+// files which import them directly or transitively.
+func dependentFiles(c *config.Config, declarations []jpos.Location) []string {
+	seen := make(map[string]bool)
+
+	var files []string
+	add := func(filename string) {
+		if !seen[filename] {
+			seen[filename] = true
+			files = append(files, filename)
+		}
+	}
+
+	for i := range declarations {
+		add(declarations[i].URI())
+	}
+
+	for i := range declarations {
+		for _, importer := range c.ImportIndex().Importers(declarations[i].URI()) {
+			add(importer)
+		}
+	}
+
+	return files
+}
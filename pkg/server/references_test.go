@@ -0,0 +1,93 @@
+package server
+
+import (
+	"context"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/tminor/jsonnet-language-server/pkg/analysis/lexical/token"
+	"github.com/tminor/jsonnet-language-server/pkg/config"
+	jpos "github.com/tminor/jsonnet-language-server/pkg/util/position"
+	opentracing "github.com/opentracing/opentracing-go"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func Test_workspaceReferences(t *testing.T) {
+	dir, err := ioutil.TempDir("", "")
+	require.NoError(t, err)
+	defer os.RemoveAll(dir)
+
+	files := map[string]string{
+		"lib.libsonnet": "{\n  name: 'lib',\n  greeting: 'hello ' + self.name,\n}",
+		"app.jsonnet":   "local lib = import 'lib.libsonnet';\nlib { name: 'app' }",
+		"main.jsonnet":  "local app = import 'app.jsonnet';\napp.name",
+		"other.jsonnet": "{name: 'other'}",
+	}
+
+	for name, source := range files {
+		require.NoError(t, ioutil.WriteFile(filepath.Join(dir, name), []byte(source), 0600))
+	}
+
+	path := func(name string) string {
+		return filepath.Join(dir, name)
+	}
+
+	loc := func(name string, sl, sc, el, ec int) jpos.Location {
+		return jpos.NewLocation(path(name), jpos.NewRangeFromCoords(sl, sc, el, ec))
+	}
+
+	cases := []struct {
+		name               string
+		includeDeclaration bool
+		expected           []jpos.Location
+	}{
+		{
+			name:               "include declaration",
+			includeDeclaration: true,
+			expected: []jpos.Location{
+				loc("lib.libsonnet", 2, 3, 2, 7),
+				loc("lib.libsonnet", 3, 29, 3, 33),
+				loc("app.jsonnet", 2, 7, 2, 11),
+				loc("main.jsonnet", 2, 5, 2, 9),
+			},
+		},
+		{
+			name: "references only",
+			expected: []jpos.Location{
+				loc("lib.libsonnet", 3, 29, 3, 33),
+				loc("main.jsonnet", 2, 5, 2, 9),
+			},
+		},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			span := opentracing.StartSpan("references")
+			defer span.Finish()
+			ctx := opentracing.ContextWithSpan(context.Background(), span)
+
+			c := config.New()
+			require.NoError(t, c.ImportIndex().AddDir(dir, nil))
+
+			rs, err := token.References(ctx, path("lib.libsonnet"), files["lib.libsonnet"], jpos.New(2, 3), c.NodeCache(), nil)
+			require.NoError(t, err)
+
+			var got []jpos.Location
+			wr := &workspaceReferences{
+				config:             c,
+				includeDeclaration: tc.includeDeclaration,
+				report: func(locations []jpos.Location) error {
+					got = append(got, locations...)
+					return nil
+				},
+				seen: make(map[jpos.Location]bool),
+			}
+
+			require.NoError(t, wr.find(ctx, path("lib.libsonnet"), rs))
+			assert.Equal(t, tc.expected, got)
+		})
+	}
+}
@@ -0,0 +1,70 @@
+package server
+
+import (
+	"context"
+
+	"github.com/tminor/jsonnet-language-server/pkg/analysis/lexical/token"
+	"github.com/tminor/jsonnet-language-server/pkg/config"
+	"github.com/tminor/jsonnet-language-server/pkg/lsp"
+	jpos "github.com/tminor/jsonnet-language-server/pkg/util/position"
+	"github.com/tminor/jsonnet-language-server/pkg/util/uri"
+	opentracing "github.com/opentracing/opentracing-go"
+)
+
+func textDocumentSelectionRange(ctx context.Context, r *request, c *config.Config) (interface{}, error) {
+	span := opentracing.SpanFromContext(ctx)
+	ctx = opentracing.ContextWithSpan(ctx, span)
+
+	var params lsp.SelectionRangeParams
+	if err := r.Decode(&params); err != nil {
+		return nil, err
+	}
+
+	doc, err := c.Text(ctx, params.TextDocument.URI)
+	if err != nil {
+		return nil, err
+	}
+
+	path, err := uri.ToPath(params.TextDocument.URI)
+	if err != nil {
+		return nil, err
+	}
+
+	var positions []jpos.Position
+	for _, p := range params.Positions {
+		positions = append(positions, jpos.FromLSPPosition(p))
+	}
+
+	ranges, err := token.SelectionRanges(path, doc.String(), positions)
+	if err != nil {
+		return nil, err
+	}
+
+	out := []lsp.SelectionRange{}
+	for _, chain := range ranges {
+		out = append(out, selectionRange(chain))
+	}
+
+	return out, nil
+}
+
+// selectionRange converts ranges, from the smallest to the largest, to a
+// LSP selection range with each range's parent.
+func selectionRange(ranges []jpos.Range) lsp.SelectionRange {
+	if len(ranges) == 0 {
+		return lsp.SelectionRange{}
+	}
+
+	var parent *lsp.SelectionRange
+	for i := len(ranges) - 1; i > 0; i-- {
+		parent = &lsp.SelectionRange{
+			Range:  ranges[i].ToLSP(),
+			Parent: parent,
+		}
+	}
+
+	return lsp.SelectionRange{
+		Range:  ranges[0].ToLSP(),
+		Parent: parent,
+	}
+}
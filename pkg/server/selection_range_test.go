@@ -0,0 +1,31 @@
+package server
+
+import (
+	"testing"
+
+	"github.com/tminor/jsonnet-language-server/pkg/lsp"
+	jpos "github.com/tminor/jsonnet-language-server/pkg/util/position"
+	"github.com/stretchr/testify/assert"
+)
+
+func Test_selectionRange(t *testing.T) {
+	ranges := []jpos.Range{
+		jpos.NewRangeFromCoords(2, 3, 2, 4),
+		jpos.NewRangeFromCoords(1, 1, 3, 2),
+	}
+
+	expected := lsp.SelectionRange{
+		Range: lsp.Range{
+			Start: lsp.Position{Line: 1, Character: 2},
+			End:   lsp.Position{Line: 1, Character: 3},
+		},
+		Parent: &lsp.SelectionRange{
+			Range: lsp.Range{
+				Start: lsp.Position{Line: 0, Character: 0},
+				End:   lsp.Position{Line: 2, Character: 1},
+			},
+		},
+	}
+
+	assert.Equal(t, expected, selectionRange(ranges))
+}
@@ -0,0 +1,161 @@
+package server
+
+import (
+	"context"
+
+	"github.com/tminor/jsonnet-language-server/pkg/analysis/lexical/token"
+	"github.com/tminor/jsonnet-language-server/pkg/config"
+	"github.com/tminor/jsonnet-language-server/pkg/langserver"
+	"github.com/tminor/jsonnet-language-server/pkg/lsp"
+	jpos "github.com/tminor/jsonnet-language-server/pkg/util/position"
+	"github.com/tminor/jsonnet-language-server/pkg/util/uri"
+	opentracing "github.com/opentracing/opentracing-go"
+)
+
+var (
+	semanticTokensOptions = &lsp.SemanticTokensOptions{
+		Legend: lsp.SemanticTokensLegend{
+			TokenTypes:     token.SemanticTokenTypes,
+			TokenModifiers: token.SemanticTokenModifierNames,
+		},
+		Range: true,
+		Full: &lsp.SemanticTokensFullOptions{
+			Delta: true,
+		},
+	}
+)
+
+func textDocumentSemanticTokensFull(ctx context.Context, r *request, c *config.Config) (interface{}, error) {
+	span := opentracing.SpanFromContext(ctx)
+	ctx = opentracing.ContextWithSpan(ctx, span)
+
+	var params lsp.SemanticTokensParams
+	if err := r.Decode(&params); err != nil {
+		return nil, err
+	}
+
+	tokens, err := semanticTokens(ctx, params.TextDocument.URI, c)
+	if err != nil {
+		return nil, err
+	}
+
+	data := encodeSemanticTokens(tokens)
+
+	return &lsp.SemanticTokens{
+		ResultID: c.SemanticTokens().Store(params.TextDocument.URI, data),
+		Data:     data,
+	}, nil
+}
+
+// textDocumentSemanticTokensFullDelta returns edits to the tokens previously
+// sent for a document. If the client's previous result is unknown, all
+// tokens are returned.
+func textDocumentSemanticTokensFullDelta(ctx context.Context, r *request, c *config.Config) (interface{}, error) {
+	span := opentracing.SpanFromContext(ctx)
+	ctx = opentracing.ContextWithSpan(ctx, span)
+
+	var params lsp.SemanticTokensDeltaParams
+	if err := r.Decode(&params); err != nil {
+		return nil, err
+	}
+
+	tokens, err := semanticTokens(ctx, params.TextDocument.URI, c)
+	if err != nil {
+		return nil, err
+	}
+
+	data := encodeSemanticTokens(tokens)
+
+	cache := c.SemanticTokens()
+	previous, ok := cache.Get(params.TextDocument.URI, params.PreviousResultID)
+	resultID := cache.Store(params.TextDocument.URI, data)
+
+	if !ok {
+		return &lsp.SemanticTokens{
+			ResultID: resultID,
+			Data:     data,
+		}, nil
+	}
+
+	return &lsp.SemanticTokensDelta{
+		ResultID: resultID,
+		Edits:    langserver.SemanticTokensEdits(previous, data),
+	}, nil
+}
+
+func textDocumentSemanticTokensRange(ctx context.Context, r *request, c *config.Config) (interface{}, error) {
+	span := opentracing.SpanFromContext(ctx)
+	ctx = opentracing.ContextWithSpan(ctx, span)
+
+	var params lsp.SemanticTokensRangeParams
+	if err := r.Decode(&params); err != nil {
+		return nil, err
+	}
+
+	tokens, err := semanticTokens(ctx, params.TextDocument.URI, c)
+	if err != nil {
+		return nil, err
+	}
+
+	start := jpos.FromLSPPosition(params.Range.Start)
+	end := jpos.FromLSPPosition(params.Range.End)
+
+	var inRange []token.SemanticToken
+	for _, t := range tokens {
+		if positionBefore(t.Position, start) || !positionBefore(t.Position, end) {
+			continue
+		}
+
+		inRange = append(inRange, t)
+	}
+
+	return &lsp.SemanticTokens{
+		Data: encodeSemanticTokens(inRange),
+	}, nil
+}
+
+func semanticTokens(ctx context.Context, uriStr string, c *config.Config) ([]token.SemanticToken, error) {
+	doc, err := c.Text(ctx, uriStr)
+	if err != nil {
+		return nil, err
+	}
+
+	path, err := uri.ToPath(uriStr)
+	if err != nil {
+		return nil, err
+	}
+
+	return token.SemanticTokens(path, doc.String(), c.NodeCache(), c.JsonnetLibPaths())
+}
+
+// encodeSemanticTokens encodes tokens as LSP semantic token data. Each token
+// is five integers: the line relative to the previous token, the start
+// character relative to the previous token if it is on the same line, the
+// length, the type, and the modifiers.
+func encodeSemanticTokens(tokens []token.SemanticToken) []int {
+	data := []int{}
+
+	prevLine, prevChar := 0, 0
+	for _, t := range tokens {
+		p := t.Position.ToLSP()
+
+		char := p.Character
+		if p.Line == prevLine {
+			char -= prevChar
+		}
+
+		data = append(data, p.Line-prevLine, char, t.Length, int(t.Type), int(t.Modifiers))
+
+		prevLine, prevChar = p.Line, p.Character
+	}
+
+	return data
+}
+
+func positionBefore(a, b jpos.Position) bool {
+	if a.Line() != b.Line() {
+		return a.Line() < b.Line()
+	}
+
+	return a.Column() < b.Column()
+}
@@ -0,0 +1,25 @@
+package server
+
+import (
+	"testing"
+
+	"github.com/tminor/jsonnet-language-server/pkg/analysis/lexical/token"
+	jpos "github.com/tminor/jsonnet-language-server/pkg/util/position"
+	"github.com/stretchr/testify/assert"
+)
+
+func Test_encodeSemanticTokens(t *testing.T) {
+	tokens := []token.SemanticToken{
+		{Position: jpos.New(1, 1), Length: 5, Type: token.SemanticKeyword},
+		{Position: jpos.New(1, 7), Length: 1, Type: token.SemanticVariable, Modifiers: token.SemanticDeclaration | token.SemanticReadonly},
+		{Position: jpos.New(3, 3), Length: 3, Type: token.SemanticVariable, Modifiers: token.SemanticDefaultLibrary},
+	}
+
+	expected := []int{
+		0, 0, 5, int(token.SemanticKeyword), 0,
+		0, 6, 1, int(token.SemanticVariable), int(token.SemanticDeclaration | token.SemanticReadonly),
+		2, 2, 3, int(token.SemanticVariable), int(token.SemanticDefaultLibrary),
+	}
+
+	assert.Equal(t, expected, encodeSemanticTokens(tokens))
+}
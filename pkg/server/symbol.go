@@ -28,7 +28,12 @@ func textDocumentSymbol(ctx context.Context, r *request, c *config.Config) (inte
 		return nil, err
 	}
 
-	var response []lsp.DocumentSymbol
+	return documentSymbols(symbols), nil
+}
+
+// documentSymbols converts symbols and their children to document symbols.
+func documentSymbols(symbols []token.Symbol) []lsp.DocumentSymbol {
+	response := make([]lsp.DocumentSymbol, 0)
 
 	for _, symbol := range symbols {
 		enclosingRange := symbol.Range()
@@ -41,11 +46,11 @@ func textDocumentSymbol(ctx context.Context, r *request, c *config.Config) (inte
 			Deprecated:     symbol.IsDeprecated(),
 			Range:          enclosingRange.ToLSP(),
 			SelectionRange: selectionRange.ToLSP(),
-			Children:       make([]lsp.DocumentSymbol, 0),
+			Children:       documentSymbols(symbol.Children()),
 		}
 
 		response = append(response, ds)
 	}
 
-	return response, nil
+	return response
 }
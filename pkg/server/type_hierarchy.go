@@ -0,0 +1,143 @@
+package server
+
+import (
+	"context"
+
+	"github.com/tminor/jsonnet-language-server/pkg/analysis/lexical/token"
+	"github.com/tminor/jsonnet-language-server/pkg/config"
+	"github.com/tminor/jsonnet-language-server/pkg/lsp"
+	jpos "github.com/tminor/jsonnet-language-server/pkg/util/position"
+	"github.com/tminor/jsonnet-language-server/pkg/util/uri"
+	opentracing "github.com/opentracing/opentracing-go"
+	"github.com/opentracing/opentracing-go/log"
+)
+
+func textDocumentPrepareTypeHierarchy(ctx context.Context, r *request, c *config.Config) (interface{}, error) {
+	var params lsp.TextDocumentPositionParams
+	if err := r.Decode(&params); err != nil {
+		return nil, err
+	}
+
+	doc, err := c.Text(ctx, params.TextDocument.URI)
+	if err != nil {
+		return nil, err
+	}
+
+	path, err := uri.ToPath(params.TextDocument.URI)
+	if err != nil {
+		return nil, err
+	}
+
+	pos := jpos.FromLSPPosition(params.Position)
+
+	items, err := token.PrepareTypeHierarchy(path, doc.String(), pos, c.NodeCache(), c.JsonnetLibPaths())
+	if err != nil {
+		return nil, err
+	}
+
+	return typeHierarchyItems(items), nil
+}
+
+// typeHierarchySupertypes finds the objects an object is composed from.
+func typeHierarchySupertypes(ctx context.Context, r *request, c *config.Config) (interface{}, error) {
+	var params lsp.TypeHierarchySupertypesParams
+	if err := r.Decode(&params); err != nil {
+		return nil, err
+	}
+
+	item, err := fromTypeHierarchyItem(params.Item)
+	if err != nil {
+		return nil, err
+	}
+
+	doc, err := c.Text(ctx, params.Item.URI)
+	if err != nil {
+		return nil, err
+	}
+
+	items, err := token.Supertypes(item.Location.URI(), doc.String(), item, c.NodeCache(), c.JsonnetLibPaths())
+	if err != nil {
+		return nil, err
+	}
+
+	return typeHierarchyItems(items), nil
+}
+
+// typeHierarchySubtypes finds the objects which extend an object in the file
+// it is declared in and the files which import it.
+func typeHierarchySubtypes(ctx context.Context, r *request, c *config.Config) (interface{}, error) {
+	span := opentracing.SpanFromContext(ctx)
+
+	var params lsp.TypeHierarchySubtypesParams
+	if err := r.Decode(&params); err != nil {
+		return nil, err
+	}
+
+	item, err := fromTypeHierarchyItem(params.Item)
+	if err != nil {
+		return nil, err
+	}
+
+	out := []lsp.TypeHierarchyItem{}
+
+	for _, filename := range dependentFiles(c, []jpos.Location{item.Declaration()}) {
+		if err := ctx.Err(); err != nil {
+			return nil, err
+		}
+
+		doc, err := c.Text(ctx, uri.FromPath(filename))
+		if err != nil {
+			span.LogFields(log.Error(err))
+			continue
+		}
+
+		items, err := token.Subtypes(filename, doc.String(), item, c.NodeCache(), c.JsonnetLibPaths())
+		if err != nil {
+			span.LogFields(log.Error(err))
+			continue
+		}
+
+		out = append(out, typeHierarchyItems(items)...)
+	}
+
+	return out, nil
+}
+
+func typeHierarchyItems(items []token.TypeHierarchyItem) []lsp.TypeHierarchyItem {
+	out := []lsp.TypeHierarchyItem{}
+	for i := range items {
+		out = append(out, typeHierarchyItem(items[i]))
+	}
+
+	return out
+}
+
+func typeHierarchyItem(item token.TypeHierarchyItem) lsp.TypeHierarchyItem {
+	r := item.Location.Range()
+
+	return lsp.TypeHierarchyItem{
+		Name:           item.Name,
+		Kind:           item.Kind,
+		Detail:         item.Detail,
+		URI:            uri.FromPath(item.Location.URI()),
+		Range:          r.ToLSP(),
+		SelectionRange: item.SelectionRange.ToLSP(),
+	}
+}
+
+// fromTypeHierarchyItem converts an item sent by the client back to an item.
+// Items are identified by their file and selection range.
+func fromTypeHierarchyItem(item lsp.TypeHierarchyItem) (token.TypeHierarchyItem, error) {
+	path, err := uri.ToPath(item.URI)
+	if err != nil {
+		return token.TypeHierarchyItem{}, err
+	}
+
+	return token.TypeHierarchyItem{
+		Name:           item.Name,
+		Kind:           item.Kind,
+		Detail:         item.Detail,
+		Location:       jpos.NewLocation(path, jpos.FromLSPRange(item.Range)),
+		SelectionRange: jpos.FromLSPRange(item.SelectionRange),
+	}, nil
+}
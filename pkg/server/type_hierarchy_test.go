@@ -0,0 +1,41 @@
+package server
+
+import (
+	"testing"
+
+	"github.com/tminor/jsonnet-language-server/pkg/analysis/lexical/token"
+	"github.com/tminor/jsonnet-language-server/pkg/lsp"
+	jpos "github.com/tminor/jsonnet-language-server/pkg/util/position"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func Test_typeHierarchyItem(t *testing.T) {
+	item := token.TypeHierarchyItem{
+		Name:           "base",
+		Kind:           lsp.SKObject,
+		Location:       jpos.NewLocation("/file.jsonnet", jpos.NewRangeFromCoords(1, 7, 1, 30)),
+		SelectionRange: jpos.NewRangeFromCoords(1, 7, 1, 11),
+	}
+
+	expected := lsp.TypeHierarchyItem{
+		Name: "base",
+		Kind: lsp.SKObject,
+		URI:  "file:///file.jsonnet",
+		Range: lsp.Range{
+			Start: lsp.Position{Line: 0, Character: 6},
+			End:   lsp.Position{Line: 0, Character: 29},
+		},
+		SelectionRange: lsp.Range{
+			Start: lsp.Position{Line: 0, Character: 6},
+			End:   lsp.Position{Line: 0, Character: 10},
+		},
+	}
+
+	got := typeHierarchyItem(item)
+	assert.Equal(t, expected, got)
+
+	roundTrip, err := fromTypeHierarchyItem(got)
+	require.NoError(t, err)
+	assert.Equal(t, item, roundTrip)
+}
@@ -0,0 +1,114 @@
+package server
+
+import (
+	"context"
+
+	"github.com/tminor/jsonnet-language-server/pkg/analysis/lexical/token"
+	"github.com/tminor/jsonnet-language-server/pkg/config"
+	"github.com/tminor/jsonnet-language-server/pkg/lsp"
+	"github.com/tminor/jsonnet-language-server/pkg/util/uri"
+	opentracing "github.com/opentracing/opentracing-go"
+	"github.com/opentracing/opentracing-go/log"
+)
+
+const (
+	// maxWorkspaceSymbols is the number of symbols returned if the client
+	// doesn't set a limit.
+	maxWorkspaceSymbols = 100
+)
+
+func workspaceSymbol(ctx context.Context, r *request, c *config.Config) (interface{}, error) {
+	var params lsp.WorkspaceSymbolParams
+	if err := r.Decode(&params); err != nil {
+		return nil, err
+	}
+
+	limit := params.Limit
+	if limit <= 0 {
+		limit = maxWorkspaceSymbols
+	}
+
+	return symbolInformation(c.SymbolIndex().Search(params.Query, limit)), nil
+}
+
+func symbolInformation(symbols []token.WorkspaceSymbol) []lsp.SymbolInformation {
+	out := make([]lsp.SymbolInformation, 0, len(symbols))
+
+	for i := range symbols {
+		out = append(out, lsp.SymbolInformation{
+			Name:          symbols[i].Name,
+			Kind:          symbols[i].Kind,
+			Location:      symbols[i].Location.ToLSP(),
+			ContainerName: symbols[i].Container,
+		})
+	}
+
+	return out
+}
+
+// workspaceDidChangeWatchedFiles updates the symbol and import indexes when
+// watched files are created, changed or deleted.
+func workspaceDidChangeWatchedFiles(ctx context.Context, r *request, c *config.Config) (interface{}, error) {
+	span := opentracing.SpanFromContext(ctx)
+
+	var params lsp.DidChangeWatchedFilesParams
+	if err := r.Decode(&params); err != nil {
+		return nil, err
+	}
+
+	index := c.SymbolIndex()
+	importIndex := c.ImportIndex()
+
+	for _, change := range params.Changes {
+		path, err := uri.ToPath(change.URI)
+		if err != nil {
+			span.LogFields(log.Error(err))
+			continue
+		}
+
+		if lsp.FileChangeType(change.Type) == lsp.Deleted {
+			index.Remove(path)
+			importIndex.Remove(path)
+			continue
+		}
+
+		if err := index.Update(path); err != nil {
+			span.LogFields(log.Error(err))
+		}
+
+		if err := importIndex.Update(path, c.JsonnetLibPaths()); err != nil {
+			span.LogFields(log.Error(err))
+		}
+	}
+
+	return nil, nil
+}
+
+// indexWorkspace indexes the symbols and imports in directories in the
+// background. Indexing outlives the request which started it, so it is
+// traced in its own span.
+func indexWorkspace(ctx context.Context, c *config.Config, dirs ...string) {
+	parent := opentracing.SpanFromContext(ctx)
+
+	go func() {
+		span := parent.Tracer().StartSpan(
+			"indexWorkspace",
+			opentracing.FollowsFrom(parent.Context()),
+		)
+		defer span.Finish()
+
+		for _, dir := range dirs {
+			if dir == "" {
+				continue
+			}
+
+			if err := c.SymbolIndex().AddDir(dir); err != nil {
+				span.LogFields(log.Error(err))
+			}
+
+			if err := c.ImportIndex().AddDir(dir, c.JsonnetLibPaths()); err != nil {
+				span.LogFields(log.Error(err))
+			}
+		}
+	}()
+}
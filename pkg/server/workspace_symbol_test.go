@@ -0,0 +1,35 @@
+package server
+
+import (
+	"testing"
+
+	"github.com/tminor/jsonnet-language-server/pkg/analysis/lexical/token"
+	"github.com/tminor/jsonnet-language-server/pkg/lsp"
+	"github.com/tminor/jsonnet-language-server/pkg/util/position"
+	"github.com/stretchr/testify/assert"
+)
+
+func Test_symbolInformation(t *testing.T) {
+	loc := position.NewLocation("/lib/k.libsonnet", position.NewRangeFromCoords(5, 7, 5, 10))
+
+	symbols := []token.WorkspaceSymbol{
+		{Name: "new", Container: "apps.v1.deployment", Kind: lsp.SKMethod, Location: loc},
+	}
+
+	expected := []lsp.SymbolInformation{
+		{
+			Name: "new",
+			Kind: lsp.SKMethod,
+			Location: lsp.Location{
+				URI: "file:///lib/k.libsonnet",
+				Range: lsp.Range{
+					Start: lsp.Position{Line: 4, Character: 6},
+					End:   lsp.Position{Line: 4, Character: 9},
+				},
+			},
+			ContainerName: "apps.v1.deployment",
+		},
+	}
+
+	assert.Equal(t, expected, symbolInformation(symbols))
+}
@@ -5,6 +5,7 @@ import (
 	"fmt"
 
 	"github.com/tminor/jsonnet-language-server/pkg/lsp"
+	"github.com/tminor/jsonnet-language-server/pkg/util/uri"
 	"github.com/google/go-jsonnet/ast"
 )
 
@@ -127,6 +128,11 @@ func (r *Range) String() string {
 	return fmt.Sprintf("%s-%s", r.Start.String(), r.End.String())
 }
 
+// FromLSPRange converts a LSP range to a Range.
+func FromLSPRange(r lsp.Range) Range {
+	return NewRange(FromLSPPosition(r.Start), FromLSPPosition(r.End))
+}
+
 // FromJsonnetRange converts a Jsonnet LocationRange to
 // Range.
 func FromJsonnetRange(r ast.LocationRange) Range {
@@ -170,7 +176,7 @@ func (l *Location) Range() Range {
 // ToLSP converts the Location to a LSP Location.
 func (l *Location) ToLSP() lsp.Location {
 	return lsp.Location{
-		URI:   fmt.Sprintf("file://%s", l.uri),
+		URI:   uri.FromPath(l.uri),
 		Range: l.r.ToLSP(),
 	}
 }
@@ -19,3 +19,14 @@ func ToPath(uriStr string) (string, error) {
 
 	return u.Path, nil
 }
+
+// FromPath converts a filesystem path to a file URI. Characters which are
+// not allowed in a URI path, e.g. spaces and `#`, are escaped.
+func FromPath(path string) string {
+	u := url.URL{
+		Scheme: "file",
+		Path:   path,
+	}
+
+	return u.String()
+}